@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// APIKey is a named key. Exactly one of Key and Hash is set, Hash being the
+// hex encoded SHA-256 of the key.
+type APIKey struct {
+	Name   string
+	Key    string
+	Hash   string
+	Scopes []string
+	Roles  []string
+}
+
+type APIKeyAuthenticator struct {
+	header string
+	keys   []apiKeyEntry
+}
+
+type apiKeyEntry struct {
+	key  APIKey
+	hash []byte
+}
+
+func NewAPIKeyAuthenticator(header string, keys []APIKey) (*APIKeyAuthenticator, error) {
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+
+	a := &APIKeyAuthenticator{header: header}
+	for _, key := range keys {
+		entry := apiKeyEntry{key: key}
+		if key.Hash != "" {
+			hash, err := hex.DecodeString(key.Hash)
+			if err != nil || len(hash) != sha256.Size {
+				return nil, errors.Critical.Newf("invalid sha256 hash of api key %s", key.Name)
+			}
+			entry.hash = hash
+		} else {
+			sum := sha256.Sum256([]byte(key.Key))
+			entry.hash = sum[:]
+		}
+		a.keys = append(a.keys, entry)
+	}
+
+	return a, nil
+}
+
+// ParseAPIKeys parses the "name:key[:scope,scope[:role,role]]" entries
+// separated by ';' used in env configuration, e.g. "admin:secret::admin"
+// gives a key with a role and no scopes. If hashed is set the key part is the
+// SHA-256 hash. The errors name the position of an invalid entry, never its
+// content, as it may be the key.
+func ParseAPIKeys(value string, hashed bool) (keys []APIKey, err error) {
+	for i, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.SplitN(item, ":", 4)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Critical.Newf("invalid api key entry #%d, expected name:key[:scopes[:roles]]", i+1)
+		}
+
+		key := APIKey{Name: parts[0]}
+		if hashed {
+			key.Hash = parts[1]
+		} else {
+			key.Key = parts[1]
+		}
+		if len(parts) > 2 && parts[2] != "" {
+			key.Scopes = strings.Split(parts[2], ",")
+		}
+		if len(parts) > 3 && parts[3] != "" {
+			key.Roles = strings.Split(parts[3], ",")
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (a *APIKeyAuthenticator) Authenticate(c echo.Context) (*api.Principal, error) {
+	value := c.Request().Header.Get(a.header)
+	if value == "" {
+		return nil, nil
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	var matched *APIKey
+	for i := range a.keys {
+		// compare every key to keep the timing independent of the match position
+		if subtle.ConstantTimeCompare(sum[:], a.keys[i].hash) == 1 {
+			matched = &a.keys[i].key
+		}
+	}
+
+	if matched == nil {
+		return nil, unauthorized("invalid api key")
+	}
+
+	return &api.Principal{
+		Subject: matched.Name,
+		Method:  api.AuthMethodAPIKey,
+		Scopes:  matched.Scopes,
+		Roles:   matched.Roles,
+	}, nil
+}
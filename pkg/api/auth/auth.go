@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+// Authenticator extracts the principal from a request. It returns a nil
+// principal and a nil error when the request carries no credentials it
+// understands, so the next authenticator can be tried.
+type Authenticator interface {
+	Authenticate(c echo.Context) (*api.Principal, error)
+}
+
+type Config struct {
+	// Skipper defines a function to skip middleware.
+	Skipper echoMiddleware.Skipper
+
+	// Authenticators are tried in order until one recognizes the credentials.
+	Authenticators []Authenticator
+
+	// Optional lets requests without credentials through without a principal.
+	// Invalid credentials are rejected either way.
+	Optional bool
+}
+
+// Middleware authenticates every request and stores the principal on the
+// context, see api.GetPrincipal.
+func Middleware(config Config) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = echoMiddleware.DefaultSkipper
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			for _, authenticator := range config.Authenticators {
+				principal, err := authenticator.Authenticate(c)
+				if err != nil {
+					return err
+				}
+				if principal != nil {
+					api.SetPrincipal(c, principal)
+					return next(c)
+				}
+			}
+
+			if !config.Optional {
+				return unauthorized("credentials are missing")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func unauthorized(msg string) error {
+	return errors.Unauthorized.NewWithCode(api.ErrUnauthorized.GetCode(), msg)
+}
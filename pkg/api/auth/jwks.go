@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+)
+
+// jwksClient fetches the key sets, the timeout keeps an unresponsive identity
+// provider from blocking the startup and the refreshes.
+var jwksClient = &http.Client{Timeout: 10 * time.Second}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+type setKey struct {
+	kty string
+	alg string
+	key interface{}
+}
+
+// KeySet is a JSON Web Key Set, optionally refreshed from a URL.
+type KeySet struct {
+	mu   sync.RWMutex
+	keys map[string]setKey
+}
+
+func LoadKeySetFile(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Critical.Wrapf(err, "can't read jwks file %s", path)
+	}
+
+	set := &KeySet{}
+	if err = set.load(data); err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+// LoadKeySetURL fetches the key set and keeps refreshing it until ctx is done.
+// Failed refreshes keep the previous keys.
+func LoadKeySetURL(ctx context.Context, url string, refreshInterval time.Duration) (*KeySet, error) {
+	set := &KeySet{}
+	if err := set.fetch(ctx, url); err != nil {
+		return nil, err
+	}
+
+	go func() {
+		tick := time.NewTicker(refreshInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				if err := set.fetch(ctx, url); err != nil {
+					logger.Error().Err(err).Msg("can't refresh jwks")
+				}
+			}
+		}
+	}()
+
+	return set, nil
+}
+
+// Find returns the key with the given id. Without an id the key is chosen by
+// algorithm when the set holds exactly one candidate.
+func (s *KeySet) Find(kid string, alg string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if kid != "" {
+		key, ok := s.keys[kid]
+		return key.key, ok
+	}
+
+	var found interface{}
+	candidates := 0
+	for _, key := range s.keys {
+		if key.alg == alg || (key.alg == "" && keyTypeFor(alg) == key.kty) {
+			found = key.key
+			candidates++
+		}
+	}
+
+	return found, candidates == 1
+}
+
+func (s *KeySet) fetch(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Critical.Wrapf(err, "invalid jwks url %s", url)
+	}
+
+	resp, err := jwksClient.Do(req)
+	if err != nil {
+		return errors.Temporarily.Wrapf(err, "can't fetch jwks from %s", url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Temporarily.Newf("can't fetch jwks from %s: status %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Temporarily.Wrapf(err, "can't read jwks from %s", url)
+	}
+
+	return s.load(data)
+}
+
+func (s *KeySet) load(data []byte) error {
+	var set jsonWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return errors.Critical.Wrap(err, "invalid jwks")
+	}
+
+	keys := make(map[string]setKey, len(set.Keys))
+	for i, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.publicKey()
+		if err != nil {
+			return errors.Critical.Wrapf(err, "invalid jwks key %q", jwk.Kid)
+		}
+
+		kid := jwk.Kid
+		if kid == "" {
+			kid = fmt.Sprintf("#%d", i)
+		}
+		keys[kid] = setKey{kty: jwk.Kty, alg: jwk.Alg, key: key}
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Newf("unsupported curve %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		return base64.RawURLEncoding.DecodeString(k.K)
+	default:
+		return nil, errors.Newf("unsupported key type %s", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+func keyTypeFor(alg string) string {
+	switch {
+	case strings.HasPrefix(alg, "HS"):
+		return "oct"
+	case strings.HasPrefix(alg, "RS"), strings.HasPrefix(alg, "PS"):
+		return "RSA"
+	case strings.HasPrefix(alg, "ES"):
+		return "EC"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+)
+
+var defaultAlgorithms = []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+type JWTConfig struct {
+	// Secret is the shared key for HS* tokens.
+	Secret string
+
+	// PublicKeyFile is a PEM encoded RSA or EC public key for RS*/ES* tokens.
+	PublicKeyFile string
+
+	// JWKSFile and JWKSURL are sources of a JSON Web Key Set.
+	JWKSFile string
+	JWKSURL  string
+
+	// JWKSRefreshInterval defines how often the key set is reloaded from JWKSURL.
+	// Optional. Default value 10 minutes.
+	JWKSRefreshInterval time.Duration
+
+	// Algorithms accepted in the token header.
+	// Optional. Default value all HS*, RS* and ES* algorithms.
+	Algorithms []string
+
+	Audience string
+	Issuer   string
+
+	// Claims holding the subject, scopes and roles.
+	// Optional. Default values "sub", "scope" and "roles".
+	SubjectClaim string
+	ScopeClaim   string
+	RolesClaim   string
+}
+
+type JWTAuthenticator struct {
+	cfg       JWTConfig
+	parser    *jwt.Parser
+	secret    []byte
+	rsaKey    *rsa.PublicKey
+	ecdsaKey  *ecdsa.PublicKey
+	keySet    *KeySet
+	keySource string
+}
+
+func NewJWTAuthenticator(ctx context.Context, cfg JWTConfig) (a *JWTAuthenticator, err error) {
+	if len(cfg.Algorithms) == 0 {
+		cfg.Algorithms = defaultAlgorithms
+	}
+	if cfg.SubjectClaim == "" {
+		cfg.SubjectClaim = "sub"
+	}
+	if cfg.ScopeClaim == "" {
+		cfg.ScopeClaim = "scope"
+	}
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = "roles"
+	}
+	if cfg.JWKSRefreshInterval == 0 {
+		cfg.JWKSRefreshInterval = 10 * time.Minute
+	}
+
+	a = &JWTAuthenticator{
+		cfg:    cfg,
+		parser: &jwt.Parser{ValidMethods: cfg.Algorithms},
+	}
+
+	if cfg.Secret != "" {
+		a.secret = []byte(cfg.Secret)
+	}
+
+	if cfg.PublicKeyFile != "" {
+		if err = a.loadPublicKey(cfg.PublicKeyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case cfg.JWKSFile != "":
+		a.keySet, err = LoadKeySetFile(cfg.JWKSFile)
+	case cfg.JWKSURL != "":
+		a.keySet, err = LoadKeySetURL(ctx, cfg.JWKSURL, cfg.JWKSRefreshInterval)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if a.secret == nil && a.rsaKey == nil && a.ecdsaKey == nil && a.keySet == nil {
+		return nil, errors.Critical.New("jwt authenticator has no verification keys")
+	}
+
+	return a, nil
+}
+
+func (a *JWTAuthenticator) loadPublicKey(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Critical.Wrapf(err, "can't read jwt public key %s", path)
+	}
+
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		a.rsaKey = key
+		return nil
+	}
+
+	key, err := jwt.ParseECPublicKeyFromPEM(data)
+	if err != nil {
+		return errors.Critical.Wrapf(err, "jwt public key %s is neither RSA nor EC", path)
+	}
+	a.ecdsaKey = key
+
+	return nil
+}
+
+func (a *JWTAuthenticator) Authenticate(c echo.Context) (*api.Principal, error) {
+	header := c.Request().Header.Get(echo.HeaderAuthorization)
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return nil, nil
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := a.parser.ParseWithClaims(strings.TrimSpace(header[7:]), claims, a.key); err != nil {
+		// the reason is logged only, it is of no use to the client
+		logger.Debug().Err(err).Msg("invalid token")
+		return nil, unauthorized("invalid token")
+	}
+
+	if a.cfg.Audience != "" && !claims.VerifyAudience(a.cfg.Audience, true) {
+		return nil, unauthorized("invalid token audience")
+	}
+	if a.cfg.Issuer != "" && !claims.VerifyIssuer(a.cfg.Issuer, true) {
+		return nil, unauthorized("invalid token issuer")
+	}
+
+	subject, _ := claims[a.cfg.SubjectClaim].(string)
+
+	return &api.Principal{
+		Subject: subject,
+		Method:  api.AuthMethodJWT,
+		Scopes:  stringList(claims[a.cfg.ScopeClaim]),
+		Roles:   stringList(claims[a.cfg.RolesClaim]),
+		Claims:  claims,
+	}, nil
+}
+
+func (a *JWTAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if a.keySet != nil {
+		if key, ok := a.keySet.Find(kid, token.Method.Alg()); ok {
+			return key, nil
+		}
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if a.secret != nil {
+			return a.secret, nil
+		}
+	case *jwt.SigningMethodRSA:
+		if a.rsaKey != nil {
+			return a.rsaKey, nil
+		}
+	case *jwt.SigningMethodECDSA:
+		if a.ecdsaKey != nil {
+			return a.ecdsaKey, nil
+		}
+	}
+
+	return nil, errors.Unauthorized.Newf("no key for algorithm %s", token.Method.Alg())
+}
+
+// stringList reads a claim that is either a space separated string (as in
+// the OAuth2 "scope" claim) or an array of strings.
+func stringList(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return strings.Fields(value)
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
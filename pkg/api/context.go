@@ -2,7 +2,9 @@ package api
 
 import (
 	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/echo/codec"
 	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+	"github.com/neonlabsorg/neon-service-framework/pkg/reporter"
 )
 
 type DefaultApiContext struct {
@@ -29,12 +31,18 @@ func (c *DefaultApiContext) BindAndValidateModel(model interface{}) error {
 	if err := c.bindAndValidateModel(model); err != nil {
 		return err
 	}
-	if err := c.GetValidator().Validate(model); err != nil {
+	if err := c.GetValidator().ValidateCtx(c.Request().Context(), model); err != nil {
 		return err
 	}
 	return nil
 }
 
+// Respond writes the model in the format negotiated by the Accept header:
+// JSON, MessagePack, CBOR or protobuf.
+func (c *DefaultApiContext) Respond(statusCode int, model interface{}) error {
+	return codec.Respond(c, statusCode, model)
+}
+
 func ExtendApiContext(echoCtx echo.Context) *DefaultApiContext {
 	return echoCtx.(*DefaultApiContext)
 }
@@ -46,6 +54,7 @@ type ApiContextExtender interface {
 type DefaultApiContextExtender struct {
 	validatorInstance *Validator
 	logger            logger.Logger
+	reporter          reporter.ErrorReporter
 }
 
 func NewDefaultApiContextExtender(
@@ -58,6 +67,15 @@ func NewDefaultApiContextExtender(
 	}
 }
 
+func (e *DefaultApiContextExtender) GetValidator() *Validator {
+	return e.validatorInstance
+}
+
+// SetErrorReporter sets the reporter of the panics recovered by Recover.
+func (e *DefaultApiContextExtender) SetErrorReporter(r reporter.ErrorReporter) {
+	e.reporter = r
+}
+
 func (e *DefaultApiContextExtender) ExtendDefaultApiContext(h echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) (err error) {
 		ctx := &DefaultApiContext{
@@ -1,9 +1,56 @@
 package api
 
-import "github.com/neonlabsorg/neon-service-framework/pkg/errors"
+import (
+	"net/http"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"google.golang.org/grpc/codes"
+)
 
 var (
-	ErrInternal     = errors.Internal.NewWithCode(100000001, "internal error")
-	ErrUnauthorized = errors.Unauthorized.NewWithCode(100000002, "unauthorized error")
-	ErrValidation   = errors.Validation.NewWithCode(100000003, "validation error")
+	ErrInternal = errors.Register(errors.Definition{
+		Code:    100000001,
+		Type:    errors.Internal,
+		Message: "internal error",
+		Doc:     "The request failed because of an unexpected server error.",
+	}).New()
+	ErrUnauthorized = errors.Register(errors.Definition{
+		Code:    100000002,
+		Type:    errors.Unauthorized,
+		Message: "unauthorized error",
+		Doc:     "The request has no valid credentials.",
+	}).New()
+	ErrValidation = errors.Register(errors.Definition{
+		Code:    100000003,
+		Type:    errors.Validation,
+		Message: "validation error",
+		Doc:     "The request is malformed or has invalid fields.",
+	}).New()
+	ErrAccessDenied = errors.Register(errors.Definition{
+		Code:    100000004,
+		Type:    errors.AccessDenied,
+		Message: "access denied",
+		Doc:     "The credentials do not grant access to the resource.",
+	}).New()
+
+	ErrRateLimitExceeded = errors.Register(errors.Definition{
+		Code:       100000005,
+		Type:       errors.Temporarily,
+		Message:    "rate limit exceeded",
+		Doc:        "The client sent too many requests, retry after the time given by the Retry-After header.",
+		HTTPStatus: http.StatusTooManyRequests,
+		GRPCCode:   codes.ResourceExhausted,
+	}).New()
+	ErrIdempotencyKeyReused = errors.Register(errors.Definition{
+		Code:    100000006,
+		Type:    errors.Logical,
+		Message: "idempotency key reused",
+		Doc:     "The Idempotency-Key was already used with a different request.",
+	}).New()
+	ErrIdempotencyKeyInProgress = errors.Register(errors.Definition{
+		Code:    100000007,
+		Type:    errors.Temporarily,
+		Message: "idempotency key in progress",
+		Doc:     "A request with the same Idempotency-Key is still being processed, retry later.",
+	}).New()
 )
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/echo/codec"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+// DecodeErrorResponse rebuilds the errors.Error rendered by HttpErrorHandler
+// in the response of another service, so that it keeps its type, code and
+// context, e.g.
+//
+//	if resp.StatusCode >= http.StatusBadRequest {
+//		return api.DecodeErrorResponse(resp)
+//	}
+//
+// ErrorResponseModel bodies of any codec and problem+json bodies are decoded.
+// Other bodies give an error of the type of the status. The body is read but
+// not closed.
+func DecodeErrorResponse(resp *http.Response) errors.Error {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Temporarily.Wrapf(err, "failed to read the error response with status %d", resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get(echo.HeaderContentType)
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == MIMEApplicationProblemJSON {
+		var problem ProblemModel
+		if err = json.Unmarshal(body, &problem); err == nil && problem.Status != 0 {
+			return decodeErrorModel(problemErrorModel(problem))
+		}
+	} else if cd, ok := codec.Default.Get(contentType); ok {
+		var response ErrorResponseModel
+		if err = cd.Unmarshal(body, &response); err == nil && response.Error.StatusCode != 0 {
+			return decodeErrorModel(response.Error)
+		}
+	}
+
+	message := strings.TrimSpace(string(body))
+	if message == "" {
+		message = http.StatusText(resp.StatusCode)
+	}
+	return errors.HTTPStatusType(resp.StatusCode).New(message)
+}
+
+// decodeErrorModel restores the error through its JSON form, which has the
+// fields of the model. Models of errors which are not errors.Error, e.g.
+// validation errors, get the type of their status.
+func decodeErrorModel(model HttpErrorResponseModel) errors.Error {
+	typeErrorModel(&model)
+
+	var customErr errors.Error
+	data, err := json.Marshal(model)
+	if err == nil {
+		err = json.Unmarshal(data, &customErr)
+	}
+	if err != nil {
+		return errors.HTTPStatusType(model.StatusCode).New(model.Message)
+	}
+	return customErr
+}
+
+func typeErrorModel(model *HttpErrorResponseModel) {
+	if _, ok := errors.ParseErrorType(model.Name); !ok {
+		model.Name = errors.HTTPStatusType(model.StatusCode).String()
+	}
+	for i := range model.Errors {
+		typeErrorModel(&model.Errors[i])
+	}
+}
+
+func problemErrorModel(problem ProblemModel) HttpErrorResponseModel {
+	model := HttpErrorResponseModel{
+		Message:    problem.Detail,
+		Name:       problem.Name,
+		StatusCode: problem.Status,
+		Code:       problem.Code,
+		Context:    problem.Context,
+	}
+	if model.Message == "" {
+		model.Message = problem.Title
+	}
+	for _, member := range problem.Errors {
+		model.Errors = append(model.Errors, problemErrorModel(member))
+	}
+	return model
+}
@@ -1,29 +1,76 @@
 package api
 
 import (
-	"fmt"
 	"net"
 	"net/http"
 	"reflect"
 
 	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/echo/binder"
+	"github.com/neonlabsorg/neon-service-framework/pkg/echo/codec"
 	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
-	"github.com/neonlabsorg/neon-service-framework/pkg/helpers"
-	"gopkg.in/go-playground/validator.v9"
+	"github.com/neonlabsorg/neon-service-framework/pkg/reporter"
 )
 
+// HttpErrorHandlerWithReporter returns HttpErrorHandler which also reports the
+// unexpected errors, see reporter.ShouldReport. Plain errors are reported when
+// they are rendered with a server error status.
+func HttpErrorHandlerWithReporter(r reporter.ErrorReporter) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+		if errorStatus(err) >= http.StatusInternalServerError {
+			reporter.ReportError(c.Request().Context(), r, err, RequestTags(c))
+		}
+		HttpErrorHandler(err, c)
+	}
+}
+
+// errorStatus returns the status HttpErrorHandler renders the error with.
+func errorStatus(err error) int {
+	switch e := err.(type) {
+	case *ValidateError, *binder.BindError:
+		return http.StatusBadRequest
+	case *echo.HTTPError:
+		return e.Code
+	case *RequestError:
+		return e.StatusCode()
+	}
+	return errors.HTTPStatusOf(err)
+}
+
+// RequestTags describe the request in error reports.
+func RequestTags(c echo.Context) map[string]string {
+	return map[string]string{
+		"transport": "http",
+		"method":    c.Request().Method,
+		"route":     c.Path(),
+	}
+}
+
 func HttpErrorHandler(err error, c echo.Context) {
+	// the error was rendered already, e.g. by the logger middleware
+	if c.Response().Committed {
+		return
+	}
+
 	if err, ok := err.(*net.OpError); ok && err.Op == "write" {
 		return
 	}
 
 	if ve, ok := err.(*ValidateError); ok {
-		ValidationError(c, ve.Veer, ve.Model)
+		renderValidationError(c, ve)
 		return
 	}
 
-	if ve, ok := err.(errors.Error); ok {
-		ProjectError(c, ve)
+	if be, ok := err.(*binder.BindError); ok {
+		BindError(c, be)
+		return
+	}
+
+	if errors.As(err, new(errors.Error)) {
+		ProjectError(c, err)
 		return
 	}
 
@@ -44,36 +91,46 @@ func HttpErrorHandler(err error, c echo.Context) {
 	_ = HttpError(c, statusCode, message)
 }
 
+// ProjectError renders an errors.Error, which may be wrapped by err. The
+// message is the message of err.
 func ProjectError(c echo.Context, err error) {
+	var perr errors.Error
+	if !errors.As(err, &perr) {
+		perr = errors.Internal.Wrap(err, "unexpected error")
+	}
+	if config, ok := problemConfig(c); ok {
+		_ = projectProblem(c, config, err, perr)
+		return
+	}
 
-	perr := err.(errors.Error)
-	message := perr.Error()
-	name := perr.GetType()
-	var statusCode int
-
-	switch perr.GetType() {
-	case errors.Validation, errors.Logical:
-		statusCode = 400
-	case errors.AccessDenied:
-		statusCode = 403
-	case errors.Unauthorized:
-		statusCode = 401
-	case errors.NotFound:
-		statusCode = 404
-	case errors.Temporarily:
-		statusCode = 503
-	default:
-		statusCode = 500
-	}
-
-	_ = c.JSON(statusCode, ErrorResponseModel{
-		Error: HttpErrorResponseModel{
-			Message:    message,
-			StatusCode: statusCode,
-			Name:       name.String(),
-			Context:    perr.GetContext(),
-		},
-	})
+	model := projectErrorModel(err, perr)
+
+	_ = codec.Respond(c, model.StatusCode, ErrorResponseModel{Error: model})
+}
+
+// projectErrorModel describes the error and the members of an errors.Multi
+// in its chain.
+func projectErrorModel(err error, perr errors.Error) HttpErrorResponseModel {
+	model := HttpErrorResponseModel{
+		Message:    err.Error(),
+		StatusCode: errors.HTTPStatusOf(perr),
+		Name:       perr.GetType().String(),
+		Code:       perr.GetCode(),
+		Context:    perr.GetContext(),
+	}
+
+	var multi errors.Multi
+	if errors.As(err, &multi) {
+		for _, member := range multi.Errors() {
+			var memberErr errors.Error
+			if !errors.As(member, &memberErr) {
+				memberErr = errors.Internal.Wrap(member, "unexpected error")
+			}
+			model.Errors = append(model.Errors, projectErrorModel(member, memberErr))
+		}
+	}
+
+	return model
 }
 
 func HttpError(c echo.Context, statusCode int, message ...string) error {
@@ -83,7 +140,13 @@ func HttpError(c echo.Context, statusCode int, message ...string) error {
 	} else {
 		echoError = echo.NewHTTPError(statusCode)
 	}
-	return c.JSON(echoError.Code, ErrorResponseModel{
+	if config, ok := problemConfig(c); ok {
+		return Problem(c, config, ProblemModel{
+			Status: echoError.Code,
+			Detail: echoError.Message.(string),
+		})
+	}
+	return codec.Respond(c, echoError.Code, ErrorResponseModel{
 		Error: HttpErrorResponseModel{
 			Message:    echoError.Message.(string),
 			StatusCode: echoError.Code,
@@ -113,48 +176,69 @@ func (e *RequestError) StatusCode() int {
 }
 
 func ValidationError(c echo.Context, err error, model interface{}) {
-	message := "Request is invalid"
-	fields := []ValidationErrorFieldModel{}
+	renderValidationError(c, &ValidateError{Veer: err, Model: model})
+}
 
-	if _, ok := err.(*validator.InvalidValidationError); ok {
-		message = err.Error()
-	} else {
-		reflectType := reflect.TypeOf(model)
-		if reflectType != nil && reflectType.Kind() == reflect.Ptr {
-			reflectType = reflectType.Elem()
-		}
+// renderValidationError responds with the messages in the locale asked by
+// the Accept-Language header.
+func renderValidationError(c echo.Context, ve *ValidateError) {
+	locale := ve.Messages().Locale(c.Request().Header.Get("Accept-Language"))
+	message, fields := ve.Fields(locale)
+
+	if config, ok := problemConfig(c); ok {
+		_ = Problem(c, config, ProblemModel{
+			Status: http.StatusBadRequest,
+			Detail: message,
+			Fields: fields,
+		})
+		return
+	}
 
-		for _, err := range err.(validator.ValidationErrors) {
-			jsonName := helpers.UnderScoreString(err.Field())
-			if model != nil {
-				if rField, ok := reflectType.FieldByName(err.Field()); ok {
-					jsonName = rField.Tag.Get("json")
-				}
-			}
+	_ = codec.Respond(c, http.StatusBadRequest, ValidationErrorResponseModel{
+		Error: ValidationErrorModel{
+			Message:    message,
+			Name:       "validation_error",
+			StatusCode: http.StatusBadRequest,
+			Fields:     fields,
+		},
+	})
+}
 
-			var message string
-			if err.Param() != "" {
-				message = fmt.Sprintf(`Invalid field '%s' by tag '%s = %s'`, jsonName, err.Tag(), err.Param())
-			} else {
-				message = fmt.Sprintf(`Invalid field '%s' by tag '%s'`, jsonName, err.Tag())
-			}
+// BindError renders the strict binding error in the validation error format.
+// The namespace of a field is its JSON pointer.
+func BindError(c echo.Context, err *binder.BindError) {
+	fields := make([]ValidationErrorFieldModel, 0, len(err.Fields))
+	for _, field := range err.Fields {
+		fields = append(fields, ValidationErrorFieldModel{
+			FieldName: field.Field,
+			Namespace: field.Path,
+			Tag:       field.Tag,
+			TagParam:  field.TagParam,
+			Message:   field.Message,
+		})
+	}
 
-			fields = append(fields, ValidationErrorFieldModel{
-				FieldName: jsonName,
-				Namespace: err.Namespace(),
-				Tag:       err.Tag(),
-				TagParam:  err.Param(),
-				Message:   message,
-			})
-		}
+	if config, ok := problemConfig(c); ok {
+		_ = Problem(c, config, ProblemModel{
+			Status: http.StatusBadRequest,
+			Detail: err.Message,
+			Fields: fields,
+		})
+		return
 	}
 
-	_ = c.JSON(http.StatusBadRequest, ValidationErrorResponseModel{
+	_ = codec.Respond(c, http.StatusBadRequest, ValidationErrorResponseModel{
 		Error: ValidationErrorModel{
-			Message:    message,
+			Message:    err.Message,
 			Name:       "validation_error",
 			StatusCode: http.StatusBadRequest,
 			Fields:     fields,
 		},
 	})
 }
+
+// ValidationErrorFields describes the validator error per field in the
+// default locale.
+func ValidationErrorFields(err error, model interface{}) (message string, fields []ValidationErrorFieldModel) {
+	return (&ValidateError{Veer: err, Model: model}).Fields("")
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+const (
+	MIMEApplicationProblemJSON = "application/problem+json"
+
+	// ProblemJSONContextKey holds the ProblemConfig of requests whose errors
+	// are rendered as problem details.
+	ProblemJSONContextKey = "api.problem_json"
+
+	problemTypeBlank = "about:blank"
+)
+
+// ProblemConfig defines the config for ProblemJSON middleware.
+type ProblemConfig struct {
+	// TypeURI is the prefix of the problem type, the error code is appended
+	// to it, e.g. "https://docs.example.com/errors/".
+	// Optional. Default value "", the type is "about:blank".
+	TypeURI string
+}
+
+// ProblemJSON renders the errors of the routes it is applied to as RFC 7807
+// problem details. Clients may also ask for them with the Accept header.
+func ProblemJSON() echo.MiddlewareFunc {
+	return ProblemJSONWithConfig(ProblemConfig{})
+}
+
+// ProblemJSONWithConfig returns a ProblemJSON middleware with config.
+func ProblemJSONWithConfig(config ProblemConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(ProblemJSONContextKey, config)
+			return next(c)
+		}
+	}
+}
+
+// problemConfig reports whether the errors of the request are rendered as
+// problem details.
+func problemConfig(c echo.Context) (ProblemConfig, bool) {
+	if config, ok := c.Get(ProblemJSONContextKey).(ProblemConfig); ok {
+		return config, true
+	}
+
+	for _, part := range strings.Split(c.Request().Header.Get(echo.HeaderAccept), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == MIMEApplicationProblemJSON && params["q"] != "0" {
+			return ProblemConfig{}, true
+		}
+	}
+
+	return ProblemConfig{}, false
+}
+
+// Problem renders the problem details. The type and title are derived from
+// the code and status when empty.
+func Problem(c echo.Context, config ProblemConfig, problem ProblemModel) error {
+	completeProblem(config, &problem)
+	if problem.Instance == "" {
+		problem.Instance = c.Request().URL.Path
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	return c.JSON(problem.Status, problem)
+}
+
+func completeProblem(config ProblemConfig, problem *ProblemModel) {
+	if problem.Type == "" {
+		problem.Type = problemTypeBlank
+		if config.TypeURI != "" && problem.Code != 0 {
+			problem.Type = config.TypeURI + strconv.FormatUint(uint64(problem.Code), 10)
+		}
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(problem.Status)
+	}
+	for i := range problem.Errors {
+		completeProblem(config, &problem.Errors[i])
+	}
+}
+
+func projectProblem(c echo.Context, config ProblemConfig, err error, perr errors.Error) error {
+	return Problem(c, config, projectProblemModel(err, perr))
+}
+
+// projectProblemModel describes the error and the members of an errors.Multi
+// in its chain.
+func projectProblemModel(err error, perr errors.Error) ProblemModel {
+	problem := ProblemModel{
+		Status:  errors.HTTPStatusOf(perr),
+		Detail:  err.Error(),
+		Name:    perr.GetType().String(),
+		Code:    perr.GetCode(),
+		Context: perr.GetContext(),
+	}
+
+	var multi errors.Multi
+	if errors.As(err, &multi) {
+		for _, member := range multi.Errors() {
+			var memberErr errors.Error
+			if !errors.As(member, &memberErr) {
+				memberErr = errors.Internal.Wrap(member, "unexpected error")
+			}
+			problem.Errors = append(problem.Errors, projectProblemModel(member, memberErr))
+		}
+	}
+
+	return problem
+}
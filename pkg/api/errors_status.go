@@ -0,0 +1,17 @@
+package api
+
+import (
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+// RegisterErrorStatus sets the HTTP status of the error type, e.g. for the
+// error types defined by a service. It replaces the status of built-in types.
+func RegisterErrorStatus(errorType errors.ErrorType, status int) {
+	errors.RegisterHTTPStatus(errorType, status)
+}
+
+// ErrorStatus returns the HTTP status of the error type, 500 for types
+// without a registered status.
+func ErrorStatus(errorType errors.ErrorType) int {
+	return errors.HTTPStatus(errorType)
+}
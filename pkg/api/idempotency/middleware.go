@@ -0,0 +1,299 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderReplayed       = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+
+	// storeTimeout bounds storing the outcome of a request.
+	storeTimeout = 5 * time.Second
+)
+
+// ScopeFunc separates the keys of different callers.
+type ScopeFunc func(c echo.Context) string
+
+type Config struct {
+	// Skipper defines a function to skip middleware.
+	Skipper echoMiddleware.Skipper
+
+	// Store keeps the keys and responses.
+	// Required.
+	Store Store
+
+	// Methods the middleware applies to.
+	// Optional. Default value POST and PATCH.
+	Methods []string
+
+	// Required rejects requests without the header.
+	Required bool
+
+	// TTL defines how long a completed response is replayed.
+	// Optional. Default value 24 hours.
+	TTL time.Duration
+
+	// LockTimeout defines how long a request holds the key before a retry may
+	// take it over, e.g. after a crashed replica.
+	// Optional. Default value 1 minute.
+	LockTimeout time.Duration
+
+	// ScopeFunc prefixes the stored key.
+	// Optional. Default value the principal subject, see api.GetPrincipal, or
+	// the client IP for anonymous callers.
+	ScopeFunc ScopeFunc
+
+	Logger logger.Logger
+}
+
+func Middleware(config Config) echo.MiddlewareFunc {
+	if config.Store == nil {
+		panic("idempotency middleware requires a store")
+	}
+	if config.Skipper == nil {
+		config.Skipper = echoMiddleware.DefaultSkipper
+	}
+	if len(config.Methods) == 0 {
+		config.Methods = []string{http.MethodPost, http.MethodPatch}
+	}
+	if config.TTL == 0 {
+		config.TTL = 24 * time.Hour
+	}
+	if config.LockTimeout == 0 {
+		config.LockTimeout = time.Minute
+	}
+	if config.ScopeFunc == nil {
+		config.ScopeFunc = principalScope
+	}
+	if config.Logger == nil {
+		config.Logger = logger.Get()
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if config.Skipper(c) || !hasMethod(config.Methods, req.Method) {
+				return next(c)
+			}
+
+			key := req.Header.Get(HeaderIdempotencyKey)
+			if key == "" {
+				if config.Required {
+					return errors.Validation.NewWithCode(api.ErrValidation.GetCode(), "Idempotency-Key header is required")
+				}
+				return next(c)
+			}
+			if len(key) > maxKeyLength {
+				return errors.Validation.NewWithCode(api.ErrValidation.GetCode(), "Idempotency-Key header is too long")
+			}
+
+			fingerprint, err := fingerprintRequest(req)
+			if err != nil {
+				return errors.Validation.WrapWithCode(api.ErrValidation.GetCode(), err, "can't read request body")
+			}
+
+			key = config.ScopeFunc(c) + ":" + key
+			owner := newOwner()
+			record, acquired, err := config.Store.Lock(req.Context(), key, owner, fingerprint, config.LockTimeout, config.TTL)
+			if err != nil {
+				return err
+			}
+
+			if !acquired {
+				return replay(c, record, fingerprint)
+			}
+
+			stopExtending := extendLock(config, key, owner)
+			defer stopExtending()
+			defer func() {
+				// a panicking handler must not keep the key in progress, the
+				// Recover middleware renders the panic afterwards
+				if r := recover(); r != nil {
+					stopExtending()
+					release(config, key, owner)
+					panic(r)
+				}
+			}()
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			err = next(c)
+			stopExtending()
+			if err != nil {
+				// render the error now so that it becomes part of the stored
+				// response, the error handler skips the committed response later
+				c.Error(err)
+			}
+
+			if status := c.Response().Status; status >= http.StatusInternalServerError {
+				release(config, key, owner)
+				return err
+			}
+
+			// the client may be gone already, the outcome has to be stored anyway
+			ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+			defer cancel()
+
+			if completeErr := config.Store.Complete(ctx, key, owner, c.Response().Status, storedHeader(c.Response().Header()), recorder.body.Bytes(), config.TTL); completeErr != nil {
+				config.Logger.Error().Err(completeErr).Msg("can't store idempotent response")
+			}
+
+			return err
+		}
+	}
+}
+
+// release frees the key after a failure, so that the request can be retried.
+func release(config Config, key string, owner string) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	if err := config.Store.Release(ctx, key, owner); err != nil {
+		config.Logger.Error().Err(err).Msg("can't release idempotency key")
+	}
+}
+
+// extendLock keeps the lock of the owner while the handler runs, so that a
+// retry does not take over the key of a slow request. The returned function
+// stops it and may be called more than once.
+func extendLock(config Config, key string, owner string) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		tick := time.NewTicker(config.LockTimeout / 3)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+				if err := config.Store.Extend(ctx, key, owner, config.LockTimeout); err != nil {
+					if ctx.Err() == nil {
+						config.Logger.Error().Err(err).Msg("can't extend idempotency key lock")
+					}
+					if errors.Is(err, ErrLockLost) {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+func newOwner() string {
+	owner := make([]byte, 16)
+	_, _ = rand.Read(owner)
+	return hex.EncodeToString(owner)
+}
+
+func replay(c echo.Context, record *Record, fingerprint string) error {
+	if record.Fingerprint != fingerprint {
+		return errors.Logical.NewWithCode(api.ErrIdempotencyKeyReused.GetCode(), "idempotency key was already used with a different request")
+	}
+
+	if !record.Completed {
+		return errors.Temporarily.NewWithCode(api.ErrIdempotencyKeyInProgress.GetCode(), "a request with the same idempotency key is in progress")
+	}
+
+	header := c.Response().Header()
+	for name, values := range record.Header {
+		header[name] = values
+	}
+	header.Set(HeaderReplayed, "true")
+
+	c.Response().WriteHeader(record.StatusCode)
+	_, err := c.Response().Write(record.Body)
+
+	return err
+}
+
+func fingerprintRequest(req *http.Request) (string, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.RequestURI() + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// storedHeader drops headers which describe the current request rather than
+// the response, such as rate limits.
+func storedHeader(header http.Header) http.Header {
+	stored := make(http.Header, len(header))
+	for name, values := range header {
+		if strings.HasPrefix(name, "Ratelimit-") || name == "Retry-After" || name == "Date" {
+			continue
+		}
+		stored[name] = values
+	}
+	return stored
+}
+
+// principalScope separates anonymous callers by their IP, so that a client
+// can't replay the response of another one by reusing its key.
+func principalScope(c echo.Context) string {
+	if principal := api.GetPrincipal(c); principal != nil {
+		return principal.Method + ":" + principal.Subject
+	}
+	return "ip:" + c.RealIP()
+}
+
+func hasMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
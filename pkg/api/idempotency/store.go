@@ -0,0 +1,211 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+)
+
+const DefaultPostgresTable = "idempotency_keys"
+
+// Record is the stored state of a key. A record which is not completed is a
+// lock held by the request currently processing the key, the owner.
+type Record struct {
+	Key         string
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+}
+
+// Store keeps the keys. The owner is a random id of the request holding the
+// lock, a request which lost its lock to a retry can't change the record.
+type Store interface {
+	// Lock creates a processing record for the key. If the key is already
+	// taken (completed, or locked by another request for less than
+	// lockTimeout) the existing record is returned and acquired is false.
+	// Expired records are replaced.
+	Lock(ctx context.Context, key string, owner string, fingerprint string, lockTimeout time.Duration, ttl time.Duration) (record *Record, acquired bool, err error)
+
+	// Extend keeps the lock of the owner for another lockTimeout.
+	Extend(ctx context.Context, key string, owner string, lockTimeout time.Duration) error
+
+	// Complete stores the final response of the key.
+	Complete(ctx context.Context, key string, owner string, statusCode int, header http.Header, body []byte, ttl time.Duration) error
+
+	// Release removes the lock so the request can be retried.
+	Release(ctx context.Context, key string, owner string) error
+}
+
+// ErrLockLost is returned when the lock of the owner was taken over.
+var ErrLockLost = errors.Temporarily.New("idempotency key lock was lost")
+
+type PostgresStore struct {
+	pool  *pgxpool.Pool
+	table string
+}
+
+// NewPostgresStore creates the store on a pool obtained from
+// PostgresManager.GetConnectionPool. Call Init to create the table.
+func NewPostgresStore(pool *pgxpool.Pool, table string) *PostgresStore {
+	if table == "" {
+		table = DefaultPostgresTable
+	}
+
+	return &PostgresStore{
+		pool:  pool,
+		table: pgx.Identifier{table}.Sanitize(),
+	}
+}
+
+func (s *PostgresStore) Init(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		key             TEXT PRIMARY KEY,
+		fingerprint     TEXT NOT NULL,
+		owner           TEXT,
+		completed       BOOLEAN NOT NULL DEFAULT FALSE,
+		response_status INTEGER,
+		response_header JSONB,
+		response_body   BYTEA,
+		locked_until    TIMESTAMPTZ NOT NULL,
+		expires_at      TIMESTAMPTZ NOT NULL,
+		created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`, s.table))
+	if err != nil {
+		return errors.Critical.Wrapf(err, "can't create idempotency table %s", s.table)
+	}
+
+	// tables created before the locks had owners
+	_, err = s.pool.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS owner TEXT`, s.table))
+	if err != nil {
+		return errors.Critical.Wrapf(err, "can't migrate idempotency table %s", s.table)
+	}
+
+	return nil
+}
+
+// RunCleanup removes expired keys every interval until ctx is done.
+func (s *PostgresStore) RunCleanup(ctx context.Context, interval time.Duration) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			if _, err := s.pool.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE expires_at < now()`, s.table)); err != nil {
+				logger.Error().Err(err).Msg("can't clean up idempotency table")
+			}
+		}
+	}
+}
+
+func (s *PostgresStore) Lock(ctx context.Context, key string, owner string, fingerprint string, lockTimeout time.Duration, ttl time.Duration) (*Record, bool, error) {
+	var locked string
+	err := s.pool.QueryRow(ctx, fmt.Sprintf(`
+		INSERT INTO %[1]s AS t (key, owner, fingerprint, completed, locked_until, expires_at)
+		VALUES ($1, $5, $2, FALSE, now() + make_interval(secs => $3), now() + make_interval(secs => $4))
+		ON CONFLICT (key) DO UPDATE SET
+			owner = EXCLUDED.owner,
+			fingerprint = EXCLUDED.fingerprint,
+			completed = FALSE,
+			response_status = NULL,
+			response_header = NULL,
+			response_body = NULL,
+			locked_until = EXCLUDED.locked_until,
+			expires_at = EXCLUDED.expires_at,
+			created_at = now()
+		WHERE t.expires_at < now()
+			OR (NOT t.completed AND t.locked_until < now() AND t.fingerprint = EXCLUDED.fingerprint)
+		RETURNING key`, s.table),
+		key, fingerprint, lockTimeout.Seconds(), ttl.Seconds(), owner,
+	).Scan(&locked)
+	if err == nil {
+		return nil, true, nil
+	}
+	if err != pgx.ErrNoRows {
+		return nil, false, errors.Temporarily.Wrap(err, "can't lock idempotency key")
+	}
+
+	record := &Record{Key: key}
+	var (
+		status *int32
+		header []byte
+	)
+	err = s.pool.QueryRow(ctx, fmt.Sprintf(
+		`SELECT fingerprint, completed, response_status, response_header, response_body FROM %s WHERE key = $1`, s.table,
+	), key).Scan(&record.Fingerprint, &record.Completed, &status, &header, &record.Body)
+	if err != nil {
+		return nil, false, errors.Temporarily.Wrap(err, "can't select idempotency key")
+	}
+
+	if status != nil {
+		record.StatusCode = int(*status)
+	}
+	if len(header) > 0 {
+		if err = json.Unmarshal(header, &record.Header); err != nil {
+			return nil, false, errors.Internal.Wrap(err, "invalid stored idempotency response header")
+		}
+	}
+
+	return record, false, nil
+}
+
+func (s *PostgresStore) Extend(ctx context.Context, key string, owner string, lockTimeout time.Duration) error {
+	tag, err := s.pool.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s SET locked_until = now() + make_interval(secs => $3)
+		WHERE key = $1 AND owner = $2 AND NOT completed`, s.table),
+		key, owner, lockTimeout.Seconds(),
+	)
+	if err != nil {
+		return errors.Temporarily.Wrap(err, "can't extend idempotency key lock")
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, key string, owner string, statusCode int, header http.Header, body []byte, ttl time.Duration) error {
+	encodedHeader, err := json.Marshal(header)
+	if err != nil {
+		return errors.Internal.Wrap(err, "can't encode idempotency response header")
+	}
+
+	tag, err := s.pool.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s SET
+			completed = TRUE,
+			response_status = $2,
+			response_header = $3,
+			response_body = $4,
+			expires_at = now() + make_interval(secs => $5)
+		WHERE key = $1 AND owner = $6 AND NOT completed`, s.table),
+		key, statusCode, encodedHeader, body, ttl.Seconds(), owner,
+	)
+	if err != nil {
+		return errors.Temporarily.Wrap(err, "can't store idempotency response")
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) Release(ctx context.Context, key string, owner string) error {
+	_, err := s.pool.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE key = $1 AND owner = $2 AND NOT completed`, s.table), key, owner)
+	if err != nil {
+		return errors.Temporarily.Wrap(err, "can't release idempotency key")
+	}
+
+	return nil
+}
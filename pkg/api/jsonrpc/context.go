@@ -0,0 +1,100 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+)
+
+// Context is passed to the method handlers. It embeds the context of the HTTP
+// request which carried the call.
+type Context struct {
+	echo.Context
+	request   *Request
+	validator *api.Validator
+}
+
+func (c *Context) Method() string {
+	return c.request.Method
+}
+
+func (c *Context) Params() json.RawMessage {
+	return c.request.Params
+}
+
+func (c *Context) Ctx() context.Context {
+	return c.Request().Context()
+}
+
+// BindParams decodes the params into the model and validates it. Named params
+// are decoded by json tags. Positional params are assigned to the exported
+// struct fields in declaration order, or decoded as a whole when the model is
+// not a struct.
+func (c *Context) BindParams(model interface{}) error {
+	if err := bindParams(c.request.Params, model); err != nil {
+		return err
+	}
+
+	if c.validator != nil && isStruct(model) {
+		if err := c.validator.ValidateCtx(c.Ctx(), model); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func bindParams(params json.RawMessage, model interface{}) error {
+	params = bytes.TrimSpace(params)
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return nil
+	}
+
+	if params[0] != '[' || !isStruct(model) {
+		if err := json.Unmarshal(params, model); err != nil {
+			return NewError(CodeInvalidParams, "invalid params: "+err.Error())
+		}
+		return nil
+	}
+
+	var positional []json.RawMessage
+	if err := json.Unmarshal(params, &positional); err != nil {
+		return NewError(CodeInvalidParams, "invalid params: "+err.Error())
+	}
+
+	val := reflect.ValueOf(model).Elem()
+	fields := positionalFields(val.Type())
+	if len(positional) > len(fields) {
+		return NewError(CodeInvalidParams, "invalid params: too many positional params")
+	}
+
+	for i, raw := range positional {
+		field := val.FieldByIndex(fields[i].Index)
+		if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+			return NewError(CodeInvalidParams, "invalid params: param "+fields[i].Name+": "+err.Error())
+		}
+	}
+
+	return nil
+}
+
+func positionalFields(t reflect.Type) (fields []reflect.StructField) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || strings.Split(field.Tag.Get("json"), ",")[0] == "-" {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func isStruct(model interface{}) bool {
+	t := reflect.TypeOf(model)
+	return t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
+}
@@ -0,0 +1,35 @@
+package jsonrpc
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const unknownMethod = "unknown"
+
+var (
+	metricsOnce sync.Once
+
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "jsonrpc_requests_total",
+		Help: "JSON-RPC calls by method and error code, 0 for success.",
+	}, []string{"method", "code"})
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "jsonrpc_request_duration_seconds",
+		Help:    "JSON-RPC call duration by method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+func initMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(requestsTotal, requestDuration)
+	})
+}
+
+func codeLabel(code int) string {
+	return strconv.Itoa(code)
+}
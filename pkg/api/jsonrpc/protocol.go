@@ -0,0 +1,141 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+const Version = "2.0"
+
+// Standard JSON-RPC 2.0 codes and the EIP-1474 server error codes used by
+// Ethereum style APIs.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	CodeInvalidInput        = -32000
+	CodeResourceNotFound    = -32001
+	CodeResourceUnavailable = -32002
+	CodeTransactionRejected = -32003
+	CodeMethodNotSupported  = -32004
+	CodeLimitExceeded       = -32005
+	CodeUnauthorized        = -32006
+
+	// CodeAccessDenied is the application code of authorization failures,
+	// EIP-1474 has none.
+	CodeAccessDenied = -32010
+)
+
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification reports whether the request has no id, in which case no
+// response is sent.
+func (r *Request) IsNotification() bool {
+	return len(r.ID) == 0
+}
+
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc error %d: %s", e.Code, e.Message)
+}
+
+func NewError(code int, message string, data ...interface{}) *Error {
+	e := &Error{Code: code, Message: message}
+	if len(data) > 0 {
+		e.Data = data[0]
+	}
+	return e
+}
+
+// ErrorData carries the framework error details of a mapped error.
+type ErrorData struct {
+	Name    string                          `json:"name"`
+	Code    errors.ErrorCode                `json:"code,omitempty"`
+	Context map[string]interface{}          `json:"context,omitempty"`
+	Fields  []api.ValidationErrorFieldModel `json:"fields,omitempty"`
+}
+
+// CodeByErrorType maps the framework error types onto JSON-RPC codes. Rate
+// limits are told apart by their error code, see CodeByError.
+func CodeByErrorType(t errors.ErrorType) int {
+	switch t {
+	case errors.Validation:
+		return CodeInvalidParams
+	case errors.Logical:
+		return CodeInvalidInput
+	case errors.NotFound:
+		return CodeResourceNotFound
+	case errors.Temporarily:
+		return CodeResourceUnavailable
+	case errors.Unauthorized:
+		return CodeUnauthorized
+	case errors.AccessDenied:
+		return CodeAccessDenied
+	default:
+		return CodeInternalError
+	}
+}
+
+// CodeByError maps the error onto a JSON-RPC code by its type, except for
+// api.ErrRateLimitExceeded which gets CodeLimitExceeded.
+func CodeByError(err errors.Error) int {
+	if code := err.GetCode(); code != 0 && code == api.ErrRateLimitExceeded.GetCode() {
+		return CodeLimitExceeded
+	}
+	return CodeByErrorType(err.GetType())
+}
+
+// ToError converts a handler error into a JSON-RPC error.
+func ToError(err error) *Error {
+	switch e := err.(type) {
+	case *Error:
+		return e
+	case *api.ValidateError:
+		message, fields := e.Fields("")
+		return NewError(CodeInvalidParams, message, ErrorData{Name: "validation_error", Fields: fields})
+	}
+
+	var perr errors.Error
+	if errors.As(err, &perr) {
+		return NewError(CodeByError(perr), err.Error(), ErrorData{
+			Name:    perr.GetType().String(),
+			Code:    perr.GetCode(),
+			Context: perr.GetContext(),
+		})
+	}
+	return NewError(CodeInternalError, err.Error())
+}
+
+func isBatch(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return len(data) > 0 && data[0] == '['
+}
+
+func isObject(data []byte) bool {
+	data = bytes.TrimLeft(data, " \t\r\n")
+	return len(data) > 0 && data[0] == '{'
+}
@@ -0,0 +1,194 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+)
+
+const DefaultMaxBatchSize = 100
+
+// HandlerFunc handles a method call. The result is encoded as JSON.
+type HandlerFunc func(c *Context) (interface{}, error)
+
+type Server struct {
+	mu           sync.RWMutex
+	methods      map[string]HandlerFunc
+	validator    *api.Validator
+	logger       logger.Logger
+	maxBatchSize int
+}
+
+func NewServer(log logger.Logger) *Server {
+	initMetrics()
+
+	return &Server{
+		methods:      make(map[string]HandlerFunc),
+		logger:       log,
+		maxBatchSize: DefaultMaxBatchSize,
+	}
+}
+
+// SetValidator sets the validator for the params. By default the validator of
+// api.DefaultApiContext is used.
+func (s *Server) SetValidator(validator *api.Validator) {
+	s.validator = validator
+}
+
+func (s *Server) SetMaxBatchSize(size int) {
+	s.maxBatchSize = size
+}
+
+func (s *Server) Register(method string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.methods[method] = handler
+}
+
+// Handle is the echo handler of the endpoint, e.g. e.POST("/", server.Handle).
+// Calls of a batch run one after another on the same HTTP context, so
+// handlers must not write to the response.
+func (s *Server) Handle(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return c.JSON(http.StatusOK, errorResponse(nil, NewError(CodeParseError, "can't read request body")))
+	}
+
+	if !json.Valid(body) {
+		return c.JSON(http.StatusOK, errorResponse(nil, NewError(CodeParseError, "parse error")))
+	}
+
+	if !isBatch(body) {
+		var req Request
+		if !isObject(body) || json.Unmarshal(body, &req) != nil {
+			return c.JSON(http.StatusOK, errorResponse(nil, NewError(CodeInvalidRequest, "invalid request")))
+		}
+
+		resp := s.call(c, &req)
+		if resp == nil {
+			return c.NoContent(http.StatusNoContent)
+		}
+		return c.JSON(http.StatusOK, resp)
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return c.JSON(http.StatusOK, errorResponse(nil, NewError(CodeInvalidRequest, "invalid request")))
+	}
+	if len(batch) == 0 {
+		return c.JSON(http.StatusOK, errorResponse(nil, NewError(CodeInvalidRequest, "empty batch")))
+	}
+	if s.maxBatchSize > 0 && len(batch) > s.maxBatchSize {
+		return c.JSON(http.StatusOK, errorResponse(nil, NewError(CodeInvalidRequest, fmt.Sprintf("batch is larger than %d", s.maxBatchSize))))
+	}
+
+	// the calls share the echo context, which is not safe for concurrent use
+	responses := make([]*Response, len(batch))
+	for i, raw := range batch {
+		var req Request
+		if !isObject(raw) || json.Unmarshal(raw, &req) != nil {
+			responses[i] = errorResponse(nil, NewError(CodeInvalidRequest, "invalid request"))
+			continue
+		}
+		responses[i] = s.call(c, &req)
+	}
+
+	result := make([]*Response, 0, len(responses))
+	for _, resp := range responses {
+		if resp != nil {
+			result = append(result, resp)
+		}
+	}
+	if len(result) == 0 {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// call runs a single request and returns nil for notifications.
+func (s *Server) call(c echo.Context, req *Request) (resp *Response) {
+	if req.JSONRPC != Version || req.Method == "" {
+		return errorResponse(req.ID, NewError(CodeInvalidRequest, "invalid request"))
+	}
+
+	s.mu.RLock()
+	handler, ok := s.methods[req.Method]
+	s.mu.RUnlock()
+
+	if !ok {
+		requestsTotal.WithLabelValues(unknownMethod, codeLabel(CodeMethodNotFound)).Inc()
+		if req.IsNotification() {
+			return nil
+		}
+		return errorResponse(req.ID, NewError(CodeMethodNotFound, "method not found: "+req.Method))
+	}
+
+	started := time.Now()
+	result, err := s.invoke(c, req, handler)
+	duration := time.Since(started)
+
+	requestDuration.WithLabelValues(req.Method).Observe(duration.Seconds())
+
+	if err != nil {
+		rpcErr := ToError(err)
+		requestsTotal.WithLabelValues(req.Method, codeLabel(rpcErr.Code)).Inc()
+
+		event := s.logger.Debug()
+		if rpcErr.Code == CodeInternalError {
+			event = s.logger.Error().Err(err)
+		}
+		event.Str("method", req.Method).Int("code", rpcErr.Code).Float64("duration", duration.Seconds()).Msg("jsonrpc call failed")
+
+		if req.IsNotification() {
+			return nil
+		}
+		return errorResponse(req.ID, rpcErr)
+	}
+
+	requestsTotal.WithLabelValues(req.Method, codeLabel(0)).Inc()
+	s.logger.Debug().Str("method", req.Method).Float64("duration", duration.Seconds()).Msg("jsonrpc call")
+
+	if req.IsNotification() {
+		return nil
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(req.ID, NewError(CodeInternalError, "can't encode result"))
+	}
+
+	return &Response{JSONRPC: Version, ID: req.ID, Result: encoded}
+}
+
+func (s *Server) invoke(c echo.Context, req *Request, handler HandlerFunc) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = NewError(CodeInternalError, "internal error")
+			s.logger.Error().Str("method", req.Method).Interface("panic", r).Msg("[PANIC RECOVER] jsonrpc handler")
+		}
+	}()
+
+	validator := s.validator
+	if validator == nil {
+		if apiCtx, ok := c.(*api.DefaultApiContext); ok {
+			validator = apiCtx.GetValidator()
+		}
+	}
+
+	return handler(&Context{Context: c, request: req, validator: validator})
+}
+
+func errorResponse(id json.RawMessage, err *Error) *Response {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return &Response{JSONRPC: Version, ID: id, Error: err}
+}
@@ -1,5 +1,7 @@
 package api
 
+import "github.com/neonlabsorg/neon-service-framework/pkg/errors"
+
 type ErrorResponseModel struct {
 	//
 	// Error
@@ -21,9 +23,63 @@ type HttpErrorResponseModel struct {
 	//
 	StatusCode int `json:"status_code"`
 	//
+	// Code
+	//
+	Code errors.ErrorCode `json:"code,omitempty"`
+	//
+	// Context
+	//
+	Context map[string]interface{} `json:"context"`
+	//
+	// Errors of a batch
+	//
+	Errors []HttpErrorResponseModel `json:"errors,omitempty"`
+}
+
+// ProblemModel is the RFC 7807 problem details response, rendered with the
+// application/problem+json content type.
+// swagger:model
+type ProblemModel struct {
+	//
+	// Type
+	//
+	Type string `json:"type"`
+	//
+	// Title
+	//
+	Title string `json:"title"`
+	//
+	// Status
+	//
+	Status int `json:"status"`
+	//
+	// Detail
+	//
+	Detail string `json:"detail,omitempty"`
+	//
+	// Instance
+	//
+	Instance string `json:"instance,omitempty"`
+	//
+	// Name of the error type
+	//
+	Name string `json:"name,omitempty"`
+	//
+	// Code
+	//
+	Code errors.ErrorCode `json:"code,omitempty"`
+	//
 	// Context
 	//
-	Context map[string]string `json:"context"`
+	Context map[string]interface{} `json:"context,omitempty"`
+	//
+	// Fields
+	//
+	Fields []ValidationErrorFieldModel `json:"fields,omitempty"`
+	//
+	// Errors of a batch
+	//
+	Errors []ProblemModel `json:"errors,omitempty"`
 }
 
 type SuccessResponse struct {
@@ -0,0 +1,311 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+)
+
+const (
+	DocumentPath = "/openapi.json"
+	UIPath       = "/docs"
+)
+
+// Operation describes a registered route. Request and Response are sample
+// values (usually zero structs) whose types are used to derive the schemas.
+type Operation struct {
+	ID             string
+	Summary        string
+	Description    string
+	Tags           []string
+	Deprecated     bool
+	Request        interface{}
+	Response       interface{}
+	ResponseStatus int
+	Responses      map[int]interface{}
+	Errors         []int
+}
+
+type Builder struct {
+	mu         sync.RWMutex
+	info       Info
+	operations map[string]Operation
+	excluded   map[string]bool
+}
+
+func NewBuilder(title string, version string) *Builder {
+	return &Builder{
+		info:       Info{Title: title, Version: version},
+		operations: make(map[string]Operation),
+		excluded: map[string]bool{
+			routeKey(http.MethodGet, DocumentPath):     true,
+			routeKey(http.MethodGet, UIPath):           true,
+			routeKey(http.MethodGet, uiScriptPath):     true,
+			routeKey(http.MethodGet, uiStylesheetPath): true,
+		},
+	}
+}
+
+func (b *Builder) SetInfo(info Info) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.info = info
+}
+
+// Describe attaches the operation description to the route and returns the
+// route, so it can wrap route registration inline.
+func (b *Builder) Describe(route *echo.Route, op Operation) *echo.Route {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.operations[routeKey(route.Method, route.Path)] = op
+
+	return route
+}
+
+// Exclude hides the route from the generated document.
+func (b *Builder) Exclude(route *echo.Route) *echo.Route {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.excluded[routeKey(route.Method, route.Path)] = true
+
+	return route
+}
+
+func (b *Builder) Build(routes []*echo.Route) *Document {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	generator := NewSchemaGenerator()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    b.info,
+		Paths:   make(map[string]*PathItem),
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path == routes[j].Path {
+			return routes[i].Method < routes[j].Method
+		}
+		return routes[i].Path < routes[j].Path
+	})
+
+	for _, route := range routes {
+		key := routeKey(route.Method, route.Path)
+		if b.excluded[key] {
+			continue
+		}
+
+		path, params := convertPath(route.Path)
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+		}
+
+		op := b.operations[key]
+		if item.set(route.Method, b.buildOperation(generator, route.Method, params, op)) {
+			doc.Paths[path] = item
+		}
+	}
+
+	doc.Components.Schemas = generator.Schemas()
+
+	return doc
+}
+
+func (b *Builder) buildOperation(g *SchemaGenerator, method string, pathParams []string, op Operation) *OperationObject {
+	operation := &OperationObject{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Deprecated:  op.Deprecated,
+		Responses:   make(map[string]*Response),
+	}
+
+	for _, name := range pathParams {
+		operation.Parameters = append(operation.Parameters, &Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	if op.Request != nil {
+		b.describeRequest(g, operation, method, reflect.TypeOf(op.Request))
+		operation.Responses[statusKey(http.StatusBadRequest)] = jsonResponse(g, http.StatusBadRequest, api.ValidationErrorResponseModel{})
+	}
+
+	status := op.ResponseStatus
+	if status == 0 {
+		status = http.StatusOK
+	}
+	operation.Responses[statusKey(status)] = jsonResponse(g, status, op.Response)
+
+	for code, model := range op.Responses {
+		operation.Responses[statusKey(code)] = jsonResponse(g, code, model)
+	}
+
+	for _, code := range op.Errors {
+		if _, ok := operation.Responses[statusKey(code)]; !ok {
+			operation.Responses[statusKey(code)] = jsonResponse(g, code, api.ErrorResponseModel{})
+		}
+	}
+
+	operation.Responses[statusKey(http.StatusInternalServerError)] = jsonResponse(g, http.StatusInternalServerError, api.ErrorResponseModel{})
+
+	return operation
+}
+
+// describeRequest follows binder.ModelBinder: path params, headers, cookies
+// and the query string are bound by their tags, the body is bound for every
+// method except GET, DELETE and HEAD.
+func (b *Builder) describeRequest(g *SchemaGenerator, operation *OperationObject, method string, t reflect.Type) {
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for _, param := range b.parameters(g, t, "param") {
+		for i, existing := range operation.Parameters {
+			if existing.In == "path" && existing.Name == param.Name {
+				param.In = "path"
+				param.Required = true
+				operation.Parameters[i] = param
+			}
+		}
+	}
+	operation.Parameters = append(operation.Parameters, b.parameters(g, t, "query")...)
+	operation.Parameters = append(operation.Parameters, b.parameters(g, t, "header")...)
+	operation.Parameters = append(operation.Parameters, b.parameters(g, t, "cookie")...)
+
+	switch method {
+	case http.MethodGet, http.MethodDelete, http.MethodHead:
+		return
+	}
+	if !hasBodyFields(t) {
+		return
+	}
+
+	content := map[string]*MediaType{
+		echo.MIMEApplicationJSON: {Schema: g.ObjectSchema(t, "json", append(sourceTags, "form")...)},
+	}
+	if hasTaggedField(t, "form") {
+		form := g.ObjectSchema(t, "form", append(sourceTags, "json")...)
+		content[echo.MIMEApplicationForm] = &MediaType{Schema: form}
+		content[echo.MIMEMultipartForm] = &MediaType{Schema: form}
+	}
+	operation.RequestBody = &RequestBody{Required: true, Content: content}
+}
+
+func (b *Builder) parameters(g *SchemaGenerator, t reflect.Type, in string) (params []*Parameter) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Tag.Get(in)
+		if name == "" {
+			// untagged structs are flattened by the binder
+			if fieldType := indirectType(field.Type); fieldType.Kind() == reflect.Struct && fieldType != timeType {
+				params = append(params, b.parameters(g, fieldType, in)...)
+			}
+			continue
+		}
+
+		schema := g.schemaOf(field.Type)
+		required := applyValidateTag(schema, field.Type, field.Tag.Get("validate"))
+		applyDefaultTag(schema, field)
+		params = append(params, &Parameter{
+			Name:        name,
+			In:          in,
+			Description: field.Tag.Get("description"),
+			Required:    required,
+			Schema:      schema,
+		})
+	}
+
+	return params
+}
+
+func jsonResponse(g *SchemaGenerator, status int, model interface{}) *Response {
+	response := &Response{Description: http.StatusText(status)}
+	if response.Description == "" {
+		response.Description = fmt.Sprintf("Status %d", status)
+	}
+	if model != nil {
+		response.Content = map[string]*MediaType{
+			echo.MIMEApplicationJSON: {Schema: g.Schema(model)},
+		}
+	}
+
+	return response
+}
+
+// sourceTags are the tags of the fields which are not bound from the body.
+var sourceTags = []string{"query", "param", "header", "cookie"}
+
+func hasBodyFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		if hasAnyTag(field, sourceTags) || field.Tag.Get("json") == "-" {
+			continue
+		}
+		if fieldType := indirectType(field.Type); field.Anonymous && fieldType.Kind() == reflect.Struct {
+			if hasBodyFields(fieldType) {
+				return true
+			}
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+func hasTaggedField(t reflect.Type, tag string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup(tag); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// convertPath turns echo path syntax (/users/:id/*) into OpenAPI syntax
+// (/users/{id}/{path}) and returns the parameter names.
+func convertPath(path string) (string, []string) {
+	var params []string
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		case segment == "*":
+			params = append(params, "path")
+			segments[i] = "{path}"
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+func routeKey(method string, path string) string {
+	return method + " " + path
+}
+
+func statusKey(status int) string {
+	return fmt.Sprintf("%d", status)
+}
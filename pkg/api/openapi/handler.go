@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"embed"
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// uiAssets is the documentation page. It is bundled with the service, so the
+// page works offline and runs no third party scripts on the service origin.
+//
+//go:embed ui
+var uiAssets embed.FS
+
+const (
+	uiScriptPath     = UIPath + "/ui.js"
+	uiStylesheetPath = UIPath + "/ui.css"
+)
+
+// Register serves the document built from all routes of the server at
+// DocumentPath and, if withUI is set, the documentation page at UIPath.
+func (b *Builder) Register(server *echo.Echo, withUI bool) {
+	server.GET(DocumentPath, func(c echo.Context) error {
+		return c.JSON(http.StatusOK, b.Build(server.Routes()))
+	})
+
+	if !withUI {
+		return
+	}
+
+	page := mustReadAsset("ui/index.html")
+	server.GET(UIPath, func(c echo.Context) error {
+		b.mu.RLock()
+		title := html.EscapeString(b.info.Title)
+		b.mu.RUnlock()
+
+		return c.HTML(http.StatusOK, fmt.Sprintf(string(page), title, uiStylesheetPath, DocumentPath, uiScriptPath))
+	})
+
+	script := mustReadAsset("ui/ui.js")
+	server.GET(uiScriptPath, func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "text/javascript; charset=utf-8", script)
+	})
+
+	stylesheet := mustReadAsset("ui/ui.css")
+	server.GET(uiStylesheetPath, func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "text/css; charset=utf-8", stylesheet)
+	})
+}
+
+func mustReadAsset(name string) []byte {
+	data, err := uiAssets.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,395 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	rawMessageType      = reflect.TypeOf(json.RawMessage{})
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	validateFormatAlias = map[string]string{
+		"email":       "email",
+		"url":         "uri",
+		"uri":         "uri",
+		"uuid":        "uuid",
+		"uuid4":       "uuid",
+		"ip":          "ip",
+		"ipv4":        "ipv4",
+		"ipv6":        "ipv6",
+		"hostname":    "hostname",
+		"hexadecimal": "hex",
+		"base64":      "byte",
+
+		"solana_pubkey":        "solana-pubkey",
+		"solana_signature":     "solana-signature",
+		"eth_address":          "eth-address",
+		"eth_address_checksum": "eth-address",
+		"eth_hash":             "eth-hash",
+		"hex_quantity":         "hex-quantity",
+		"uint256":              "uint256",
+	}
+)
+
+// SchemaGenerator derives schemas from Go types. Named structs are stored once
+// in components and referenced by $ref from everywhere else.
+type SchemaGenerator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func NewSchemaGenerator() *SchemaGenerator {
+	return &SchemaGenerator{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+func (g *SchemaGenerator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+// Schema returns the schema for the type of the given value.
+func (g *SchemaGenerator) Schema(i interface{}) *Schema {
+	if i == nil {
+		return nil
+	}
+
+	return g.schemaOf(reflect.TypeOf(i))
+}
+
+// ObjectSchema returns an inline object schema of a struct, skipping fields
+// tagged with any of the excluded tags.
+func (g *SchemaGenerator) ObjectSchema(t reflect.Type, tag string, excludeTags ...string) *Schema {
+	t = indirectType(t)
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	if t.Kind() != reflect.Struct {
+		return g.schemaOf(t)
+	}
+
+	g.fillProperties(schema, t, tag, excludeTags)
+
+	return schema
+}
+
+func (g *SchemaGenerator) schemaOf(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+
+	schema := g.baseSchema(t)
+	if nullable && schema.Ref == "" {
+		schema.Nullable = true
+	}
+
+	return schema
+}
+
+func (g *SchemaGenerator) baseSchema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	if t.Kind() != reflect.Struct && (t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType)) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64", Minimum: float(0)}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32", Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) ||
+			t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+			return &Schema{Type: "string"}
+		}
+		return g.structRef(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *SchemaGenerator) structRef(t reflect.Type) *Schema {
+	if t.Name() == "" {
+		return g.ObjectSchema(t, "json")
+	}
+
+	if name, ok := g.names[t]; ok {
+		return &Schema{Ref: refPrefix + name}
+	}
+
+	name := t.Name()
+	if _, taken := g.schemas[name]; taken {
+		name = strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + t.Name()
+	}
+
+	g.names[t] = name
+	// reserve the name before descending so recursive types terminate
+	g.schemas[name] = &Schema{}
+	*g.schemas[name] = *g.ObjectSchema(t, "json")
+
+	return &Schema{Ref: refPrefix + name}
+}
+
+func (g *SchemaGenerator) fillProperties(schema *Schema, t reflect.Type, tag string, excludeTags []string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		if hasAnyTag(field, excludeTags) {
+			continue
+		}
+
+		name, omitEmpty, skip := fieldName(field, tag)
+		if skip {
+			continue
+		}
+
+		fieldType := indirectType(field.Type)
+		if field.Anonymous && field.Tag.Get(tag) == "" && fieldType.Kind() == reflect.Struct {
+			g.fillProperties(schema, fieldType, tag, excludeTags)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		property := g.schemaOf(field.Type)
+		if description := field.Tag.Get("description"); description != "" {
+			property = withDescription(property, description)
+		}
+
+		required := applyValidateTag(property, field.Type, field.Tag.Get("validate"))
+		applyDefaultTag(property, field)
+		schema.Properties[name] = property
+		if required && !omitEmpty {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// applyValidateTag maps go-playground validator rules onto schema
+// constraints and reports whether the field is required.
+func applyValidateTag(schema *Schema, t reflect.Type, tag string) (required bool) {
+	if tag == "" {
+		return false
+	}
+
+	t = indirectType(t)
+	rules := strings.Split(tag, ",")
+	for i, rule := range rules {
+		name, param := rule, ""
+		if idx := strings.Index(rule, "="); idx >= 0 {
+			name, param = rule[:idx], rule[idx+1:]
+		}
+
+		if name == "required" {
+			required = true
+			continue
+		}
+		if schema.Ref != "" || len(schema.AllOf) > 0 {
+			continue
+		}
+
+		switch name {
+		case "dive":
+			// rules after dive apply to elements
+			if schema.Items != nil {
+				applyValidateTag(schema.Items, t.Elem(), strings.Join(rules[i+1:], ","))
+			}
+			return required
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, enumValue(t, value))
+			}
+		case "len":
+			setBound(schema, t, param, true, false)
+			setBound(schema, t, param, false, false)
+		case "min", "gte":
+			setBound(schema, t, param, true, false)
+		case "max", "lte":
+			setBound(schema, t, param, false, false)
+		case "gt":
+			setBound(schema, t, param, true, true)
+		case "lt":
+			setBound(schema, t, param, false, true)
+		default:
+			if format, ok := validateFormatAlias[name]; ok {
+				schema.Format = format
+			}
+		}
+	}
+
+	return required
+}
+
+func setBound(schema *Schema, t reflect.Type, param string, lower bool, exclusive bool) {
+	switch schema.Type {
+	case "string":
+		// structs encoded as strings, e.g. big.Int and decimal.Decimal, are
+		// compared by value rather than length, see api.NewValidator
+		if t.Kind() == reflect.Struct {
+			return
+		}
+		if n, err := strconv.ParseUint(param, 10, 64); err == nil {
+			if lower {
+				schema.MinLength = &n
+			} else {
+				schema.MaxLength = &n
+			}
+		}
+	case "array":
+		if n, err := strconv.ParseUint(param, 10, 64); err == nil {
+			if lower {
+				schema.MinItems = &n
+			} else {
+				schema.MaxItems = &n
+			}
+		}
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(param, 64); err == nil {
+			if lower {
+				schema.Minimum = &n
+				schema.ExclusiveMinimum = exclusive
+			} else {
+				schema.Maximum = &n
+				schema.ExclusiveMaximum = exclusive
+			}
+		}
+	}
+}
+
+func enumValue(t reflect.Type, value string) interface{} {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	}
+
+	return value
+}
+
+func withDescription(schema *Schema, description string) *Schema {
+	if schema.Ref != "" {
+		// siblings of $ref are ignored in OpenAPI 3.0, so wrap it
+		return &Schema{Description: description, AllOf: []*Schema{schema}}
+	}
+	schema.Description = description
+
+	return schema
+}
+
+func fieldName(field reflect.StructField, tag string) (name string, omitEmpty bool, skip bool) {
+	value := field.Tag.Get(tag)
+	if value == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(value, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, option := range parts[1:] {
+		if option == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, false
+}
+
+// applyDefaultTag documents the value set by the default tag of the binder.
+func applyDefaultTag(schema *Schema, field reflect.StructField) {
+	value, ok := field.Tag.Lookup("default")
+	if !ok || schema.Ref != "" {
+		return
+	}
+
+	switch schema.Type {
+	case "integer":
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			schema.Default = v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			schema.Default = v
+		}
+	case "boolean":
+		if v, err := strconv.ParseBool(value); err == nil {
+			schema.Default = v
+		}
+	case "array":
+		schema.Default = strings.Split(value, ",")
+	default:
+		schema.Default = value
+	}
+}
+
+func hasAnyTag(field reflect.StructField, tags []string) bool {
+	for _, tag := range tags {
+		if _, ok := field.Tag.Lookup(tag); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func float(f float64) *float64 {
+	return &f
+}
+
+const refPrefix = "#/components/schemas/"
@@ -0,0 +1,261 @@
+// Renders the OpenAPI document of the service. The page is served by the
+// service itself, so no third party scripts are loaded.
+(function () {
+  'use strict';
+
+  var methods = ['get', 'post', 'put', 'patch', 'delete', 'head', 'options'];
+  var root = document.getElementById('openapi-ui');
+  var spec;
+
+  function el(tag, className, text) {
+    var node = document.createElement(tag);
+    if (className) {
+      node.className = className;
+    }
+    if (text !== undefined) {
+      node.textContent = text;
+    }
+    return node;
+  }
+
+  function resolve(schema) {
+    var seen = 0;
+    while (schema && schema.$ref && seen++ < 32) {
+      var name = schema.$ref.replace('#/components/schemas/', '');
+      schema = (spec.components && spec.components.schemas || {})[name];
+    }
+    return schema || {};
+  }
+
+  // example builds a sample value of the schema.
+  function example(schema, depth) {
+    schema = resolve(schema);
+    if (depth > 8) {
+      return null;
+    }
+    if (schema.allOf) {
+      var merged = {};
+      schema.allOf.forEach(function (part) {
+        var value = example(part, depth + 1);
+        if (value && typeof value === 'object') {
+          Object.assign(merged, value);
+        }
+      });
+      return merged;
+    }
+    if (schema.default !== undefined) {
+      return schema.default;
+    }
+    if (schema.enum && schema.enum.length) {
+      return schema.enum[0];
+    }
+    switch (schema.type) {
+      case 'object':
+        var object = {};
+        Object.keys(schema.properties || {}).forEach(function (key) {
+          object[key] = example(schema.properties[key], depth + 1);
+        });
+        if (schema.additionalProperties) {
+          object.key = example(schema.additionalProperties, depth + 1);
+        }
+        return object;
+      case 'array':
+        return [example(schema.items, depth + 1)];
+      case 'integer':
+      case 'number':
+        return schema.minimum !== undefined ? schema.minimum : 0;
+      case 'boolean':
+        return false;
+      case 'string':
+        if (schema.format === 'date-time') {
+          return new Date(0).toISOString();
+        }
+        return schema.format || 'string';
+    }
+    return null;
+  }
+
+  function json(value) {
+    return JSON.stringify(value, null, 2);
+  }
+
+  function firstContent(content) {
+    var types = Object.keys(content || {});
+    if (!types.length) {
+      return null;
+    }
+    var type = types.indexOf('application/json') >= 0 ? 'application/json' : types[0];
+    return { type: type, schema: content[type].schema };
+  }
+
+  function parametersTable(parameters, inputs) {
+    var table = el('table');
+    var head = el('tr');
+    ['Name', 'In', 'Type', 'Description', 'Value'].forEach(function (title) {
+      head.appendChild(el('th', '', title));
+    });
+    table.appendChild(head);
+
+    parameters.forEach(function (param) {
+      var row = el('tr');
+      row.appendChild(el('td', 'path', param.name + (param.required ? ' *' : '')));
+      row.appendChild(el('td', '', param.in));
+      row.appendChild(el('td', '', resolve(param.schema).type || ''));
+      row.appendChild(el('td', '', param.description || ''));
+      var cell = el('td');
+      var input = el('input');
+      input.placeholder = param.name;
+      inputs.push({ param: param, input: input });
+      cell.appendChild(input);
+      row.appendChild(cell);
+      table.appendChild(row);
+    });
+    return table;
+  }
+
+  function requestURL(path, inputs) {
+    var query = [];
+    inputs.forEach(function (item) {
+      var value = item.input.value;
+      if (value === '') {
+        return;
+      }
+      if (item.param.in === 'path') {
+        path = path.replace('{' + item.param.name + '}', encodeURIComponent(value));
+      } else if (item.param.in === 'query') {
+        query.push(encodeURIComponent(item.param.name) + '=' + encodeURIComponent(value));
+      }
+    });
+    return path + (query.length ? '?' + query.join('&') : '');
+  }
+
+  function execute(method, path, inputs, body, output) {
+    var headers = { Accept: 'application/json' };
+    inputs.forEach(function (item) {
+      if (item.param.in === 'header' && item.input.value !== '') {
+        headers[item.param.name] = item.input.value;
+      }
+    });
+
+    var init = { method: method.toUpperCase(), headers: headers };
+    if (body) {
+      headers['Content-Type'] = body.type;
+      init.body = body.textarea.value;
+    }
+
+    output.textContent = 'Loading...';
+    fetch(requestURL(path, inputs), init).then(function (resp) {
+      return resp.text().then(function (text) {
+        try {
+          text = json(JSON.parse(text));
+        } catch (e) {
+          // not JSON, shown as is
+        }
+        output.textContent = resp.status + ' ' + resp.statusText + '\n\n' + text;
+      });
+    }).catch(function (err) {
+      output.textContent = String(err);
+    });
+  }
+
+  function renderOperation(method, path, op) {
+    var details = el('details', 'operation' + (op.deprecated ? ' deprecated' : ''));
+    var summary = el('summary');
+    summary.appendChild(el('span', 'method ' + method, method));
+    summary.appendChild(el('span', 'path', path));
+    summary.appendChild(el('span', 'summary', op.summary || ''));
+    details.appendChild(summary);
+
+    var body = el('div', 'operation-body');
+    if (op.description) {
+      body.appendChild(el('p', '', op.description));
+    }
+
+    var inputs = [];
+    if (op.parameters && op.parameters.length) {
+      body.appendChild(el('h4', '', 'Parameters'));
+      body.appendChild(parametersTable(op.parameters, inputs));
+    }
+
+    var requestBody;
+    var content = op.requestBody && firstContent(op.requestBody.content);
+    if (content) {
+      body.appendChild(el('h4', '', 'Request body (' + content.type + ')'));
+      var textarea = el('textarea');
+      textarea.value = json(example(content.schema, 0));
+      body.appendChild(textarea);
+      requestBody = { type: content.type, textarea: textarea };
+    }
+
+    body.appendChild(el('h4', '', 'Responses'));
+    var responses = el('table');
+    Object.keys(op.responses || {}).sort().forEach(function (status) {
+      var response = op.responses[status];
+      var row = el('tr');
+      row.appendChild(el('td', 'path', status));
+      var cell = el('td');
+      cell.appendChild(el('div', '', response.description || ''));
+      var responseContent = firstContent(response.content);
+      if (responseContent) {
+        cell.appendChild(el('pre', '', json(example(responseContent.schema, 0))));
+      }
+      row.appendChild(cell);
+      responses.appendChild(row);
+    });
+    body.appendChild(responses);
+
+    var button = el('button', '', 'Execute');
+    var output = el('pre');
+    button.addEventListener('click', function () {
+      execute(method, path, inputs, requestBody, output);
+    });
+    body.appendChild(button);
+    body.appendChild(output);
+
+    details.appendChild(body);
+    return details;
+  }
+
+  function render() {
+    var header = el('div');
+    header.appendChild(el('h1', '', spec.info.title || 'API'));
+    header.appendChild(el('span', 'version', 'Version ' + (spec.info.version || '') + ' · OpenAPI ' + spec.openapi));
+    if (spec.info.description) {
+      header.appendChild(el('p', '', spec.info.description));
+    }
+    root.appendChild(header);
+
+    var groups = {};
+    Object.keys(spec.paths || {}).sort().forEach(function (path) {
+      var item = spec.paths[path];
+      methods.forEach(function (method) {
+        var op = item[method];
+        if (!op) {
+          return;
+        }
+        (op.tags && op.tags.length ? op.tags : ['default']).forEach(function (tag) {
+          (groups[tag] = groups[tag] || []).push(renderOperation(method, path, op));
+        });
+      });
+    });
+
+    Object.keys(groups).sort().forEach(function (tag) {
+      root.appendChild(el('h2', '', tag));
+      groups[tag].forEach(function (node) {
+        root.appendChild(node);
+      });
+    });
+  }
+
+  fetch(root.getAttribute('data-document')).then(function (resp) {
+    if (!resp.ok) {
+      throw new Error('failed to load the document: ' + resp.status);
+    }
+    return resp.json();
+  }).then(function (doc) {
+    spec = doc;
+    render();
+  }).catch(function (err) {
+    root.appendChild(el('p', 'error', String(err)));
+  });
+})();
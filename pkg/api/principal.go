@@ -0,0 +1,109 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+const principalContextKey = "api.principal"
+
+const (
+	AuthMethodJWT    = "jwt"
+	AuthMethodAPIKey = "api_key"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject string
+	Method  string
+	Scopes  []string
+	Roles   []string
+	Claims  map[string]interface{}
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return contains(p.Scopes, scope)
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return contains(p.Roles, role)
+}
+
+func SetPrincipal(c echo.Context, principal *Principal) {
+	c.Set(principalContextKey, principal)
+}
+
+func GetPrincipal(c echo.Context) *Principal {
+	principal, _ := c.Get(principalContextKey).(*Principal)
+	return principal
+}
+
+func (c *DefaultApiContext) GetPrincipal() *Principal {
+	return GetPrincipal(c)
+}
+
+func (c *DefaultApiContext) GetClaims() map[string]interface{} {
+	principal := c.GetPrincipal()
+	if principal == nil {
+		return nil
+	}
+	return principal.Claims
+}
+
+// RequireScopes returns a route middleware which passes only principals
+// holding all of the given scopes.
+func RequireScopes(scopes ...string) echo.MiddlewareFunc {
+	return requirePrincipal(func(p *Principal) []string {
+		var missing []string
+		for _, scope := range scopes {
+			if !p.HasScope(scope) {
+				missing = append(missing, scope)
+			}
+		}
+		return missing
+	}, "scopes")
+}
+
+// RequireRoles returns a route middleware which passes only principals
+// holding at least one of the given roles.
+func RequireRoles(roles ...string) echo.MiddlewareFunc {
+	return requirePrincipal(func(p *Principal) []string {
+		for _, role := range roles {
+			if p.HasRole(role) {
+				return nil
+			}
+		}
+		return roles
+	}, "roles")
+}
+
+func requirePrincipal(check func(p *Principal) []string, kind string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal := GetPrincipal(c)
+			if principal == nil {
+				return errors.Unauthorized.NewWithCode(ErrUnauthorized.GetCode(), "authentication required")
+			}
+
+			if missing := check(principal); len(missing) > 0 {
+				return errors.AccessDenied.NewfWithCode(
+					ErrAccessDenied.GetCode(),
+					"access denied: required %s: %s", kind, strings.Join(missing, ", "),
+				)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	state   State
+	expires time.Time
+}
+
+// MemoryStore keeps the state in process memory, so limits are not shared
+// between replicas.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+}
+
+// NewMemoryStore creates the store and removes expired keys every
+// cleanupInterval, one minute if it is not positive, until ctx is done.
+func NewMemoryStore(ctx context.Context, cleanupInterval time.Duration) *MemoryStore {
+	s := &MemoryStore{entries: make(map[string]*memoryEntry)}
+
+	if cleanupInterval <= 0 {
+		cleanupInterval = time.Minute
+	}
+
+	go func() {
+		tick := time.NewTicker(cleanupInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-tick.C:
+				s.cleanup(now)
+			}
+		}
+	}()
+
+	return s
+}
+
+func (s *MemoryStore) Update(_ context.Context, key string, ttl time.Duration, fn func(state *State, now time.Time)) error {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || now.After(entry.expires) {
+		entry = &memoryEntry{}
+		s.entries[key] = entry
+	}
+
+	fn(&entry.state, now)
+	entry.expires = now.Add(ttl)
+
+	return nil
+}
+
+func (s *MemoryStore) cleanup(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, entry := range s.entries {
+		if now.After(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
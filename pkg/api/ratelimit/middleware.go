@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// KeyFunc returns the key requests are counted by.
+type KeyFunc func(c echo.Context) string
+
+type Config struct {
+	// Skipper defines a function to skip middleware.
+	Skipper echoMiddleware.Skipper
+
+	// Algorithm decides whether a request is allowed.
+	// Required.
+	Algorithm Algorithm
+
+	// Store keeps the per-key state.
+	// Required.
+	Store Store
+
+	// KeyFunc extracts the key of a request.
+	// Optional. Default value ByIP.
+	KeyFunc KeyFunc
+
+	// Prefix separates the keys of several limiters sharing a store.
+	Prefix string
+
+	// FailClosed rejects requests when the store is unavailable. By default
+	// such requests are let through.
+	FailClosed bool
+
+	Logger logger.Logger
+}
+
+func ByIP(c echo.Context) string {
+	return "ip:" + c.RealIP()
+}
+
+func ByRoute(c echo.Context) string {
+	return "route:" + c.Request().Method + " " + c.Path()
+}
+
+// ByPrincipal counts authenticated requests by subject and anonymous ones by IP.
+func ByPrincipal(c echo.Context) string {
+	if principal := api.GetPrincipal(c); principal != nil {
+		return "principal:" + principal.Method + ":" + principal.Subject
+	}
+	return ByIP(c)
+}
+
+// ByHeader counts requests by a header such as the API key, falling back to
+// the client IP when the header is missing. The value is hashed, so that the
+// store does not keep secrets.
+func ByHeader(header string) KeyFunc {
+	return func(c echo.Context) string {
+		if value := c.Request().Header.Get(header); value != "" {
+			hash := sha256.Sum256([]byte(value))
+			return "header:" + hex.EncodeToString(hash[:])
+		}
+		return ByIP(c)
+	}
+}
+
+// Compose joins the keys of several functions, e.g. Compose(ByIP, ByRoute)
+// limits every client on every route separately.
+func Compose(funcs ...KeyFunc) KeyFunc {
+	return func(c echo.Context) string {
+		keys := make([]string, len(funcs))
+		for i, fn := range funcs {
+			keys[i] = fn(c)
+		}
+		return strings.Join(keys, "|")
+	}
+}
+
+func Middleware(config Config) echo.MiddlewareFunc {
+	if config.Algorithm == nil || config.Store == nil {
+		panic("rate limit middleware requires algorithm and store")
+	}
+	if v, ok := config.Algorithm.(interface{ validate() error }); ok {
+		if err := v.validate(); err != nil {
+			panic(err)
+		}
+	}
+	if config.Skipper == nil {
+		config.Skipper = echoMiddleware.DefaultSkipper
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = ByIP
+	}
+	if config.Logger == nil {
+		config.Logger = logger.Get()
+	}
+
+	ttl := config.Algorithm.TTL()
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			var result Result
+			err := config.Store.Update(c.Request().Context(), config.Prefix+config.KeyFunc(c), ttl, func(state *State, now time.Time) {
+				result = config.Algorithm.Take(state, now)
+			})
+			if err != nil {
+				config.Logger.Error().Err(err).Msg("rate limit store is unavailable")
+				if config.FailClosed {
+					return errors.Temporarily.Wrap(err, "rate limit store is unavailable")
+				}
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(HeaderRateLimitLimit, strconv.FormatInt(result.Limit, 10))
+			header.Set(HeaderRateLimitRemaining, strconv.FormatInt(result.Remaining, 10))
+			header.Set(HeaderRateLimitReset, ceilSeconds(result.Reset))
+
+			if !result.Allowed {
+				header.Set(HeaderRetryAfter, ceilSeconds(result.RetryAfter))
+				return errors.Temporarily.NewWithCode(api.ErrRateLimitExceeded.GetCode(), "rate limit exceeded")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
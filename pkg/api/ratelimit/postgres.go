@@ -0,0 +1,134 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+)
+
+const DefaultPostgresTable = "rate_limits"
+
+// PostgresStore keeps the state in a table, so all replicas using the same
+// database share the limits. Rows are locked for the duration of an update
+// and the database clock is used for all replicas.
+type PostgresStore struct {
+	pool  *pgxpool.Pool
+	table string
+}
+
+// NewPostgresStore creates the store on a pool obtained from
+// PostgresManager.GetConnectionPool. Call Init to create the table.
+func NewPostgresStore(pool *pgxpool.Pool, table string) *PostgresStore {
+	if table == "" {
+		table = DefaultPostgresTable
+	}
+
+	return &PostgresStore{
+		pool:  pool,
+		table: pgx.Identifier{table}.Sanitize(),
+	}
+}
+
+func (s *PostgresStore) Init(ctx context.Context) error {
+	_, err := s.pool.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		key          TEXT PRIMARY KEY,
+		tokens       DOUBLE PRECISION NOT NULL DEFAULT 0,
+		count        BIGINT NOT NULL DEFAULT 0,
+		prev_count   BIGINT NOT NULL DEFAULT 0,
+		window_start TIMESTAMPTZ,
+		updated_at   TIMESTAMPTZ,
+		expires_at   TIMESTAMPTZ NOT NULL
+	)`, s.table))
+	if err != nil {
+		return errors.Critical.Wrapf(err, "can't create rate limit table %s", s.table)
+	}
+
+	return nil
+}
+
+// RunCleanup removes expired keys every interval until ctx is done.
+func (s *PostgresStore) RunCleanup(ctx context.Context, interval time.Duration) {
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			if _, err := s.pool.Exec(ctx, fmt.Sprintf(`DELETE FROM %s WHERE expires_at < now()`, s.table)); err != nil {
+				logger.Error().Err(err).Msg("can't clean up rate limit table")
+			}
+		}
+	}
+}
+
+func (s *PostgresStore) Update(ctx context.Context, key string, ttl time.Duration, fn func(state *State, now time.Time)) (err error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return errors.Temporarily.Wrap(err, "can't begin rate limit transaction")
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(
+		`INSERT INTO %s (key, expires_at) VALUES ($1, now()) ON CONFLICT (key) DO NOTHING`, s.table,
+	), key)
+	if err != nil {
+		return errors.Temporarily.Wrap(err, "can't insert rate limit key")
+	}
+
+	var (
+		state       State
+		windowStart *time.Time
+		updatedAt   *time.Time
+		expiresAt   time.Time
+		now         time.Time
+	)
+	err = tx.QueryRow(ctx, fmt.Sprintf(
+		`SELECT tokens, count, prev_count, window_start, updated_at, expires_at, now() FROM %s WHERE key = $1 FOR UPDATE`, s.table,
+	), key).Scan(&state.Tokens, &state.Count, &state.PrevCount, &windowStart, &updatedAt, &expiresAt, &now)
+	if err != nil {
+		return errors.Temporarily.Wrap(err, "can't select rate limit state")
+	}
+
+	if now.After(expiresAt) {
+		state = State{}
+	} else {
+		if windowStart != nil {
+			state.WindowStart = *windowStart
+		}
+		if updatedAt != nil {
+			state.UpdatedAt = *updatedAt
+		}
+	}
+
+	fn(&state, now)
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(
+		`UPDATE %s SET tokens = $2, count = $3, prev_count = $4, window_start = $5, updated_at = $6, expires_at = $7 WHERE key = $1`, s.table,
+	), key, state.Tokens, state.Count, state.PrevCount, nullTime(state.WindowStart), nullTime(state.UpdatedAt), now.Add(ttl))
+	if err != nil {
+		return errors.Temporarily.Wrap(err, "can't update rate limit state")
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return errors.Temporarily.Wrap(err, "can't commit rate limit transaction")
+	}
+
+	return nil
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
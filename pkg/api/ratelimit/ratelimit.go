@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// State is the persisted per-key state shared by all algorithms. Each
+// algorithm uses only the fields it needs.
+type State struct {
+	Tokens      float64
+	Count       int64
+	PrevCount   int64
+	WindowStart time.Time
+	UpdatedAt   time.Time
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+type Algorithm interface {
+	// Take consumes one request from the state and reports the decision.
+	Take(state *State, now time.Time) Result
+
+	// TTL is how long the state of an idle key has to be kept.
+	TTL() time.Duration
+}
+
+// Store keeps the per-key state. Update must run fn atomically for the key,
+// passing the current state (zero for unknown or expired keys) and the
+// store's clock, and persist the state after fn returns.
+type Store interface {
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(state *State, now time.Time)) error
+}
+
+// TokenBucket refills Rate tokens per Period up to Burst; every request takes
+// one token.
+type TokenBucket struct {
+	Rate   int64
+	Period time.Duration
+	Burst  int64
+}
+
+func (a TokenBucket) Take(state *State, now time.Time) Result {
+	burst := float64(a.burst())
+	perSecond := float64(a.Rate) / a.Period.Seconds()
+
+	if state.UpdatedAt.IsZero() {
+		state.Tokens = burst
+	} else if elapsed := now.Sub(state.UpdatedAt).Seconds(); elapsed > 0 {
+		state.Tokens = math.Min(burst, state.Tokens+elapsed*perSecond)
+	}
+	state.UpdatedAt = now
+
+	result := Result{Limit: a.burst()}
+	if state.Tokens >= 1 {
+		state.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - state.Tokens) / perSecond)
+	}
+
+	result.Remaining = int64(state.Tokens)
+	result.Reset = seconds((burst - state.Tokens) / perSecond)
+
+	return result
+}
+
+func (a TokenBucket) TTL() time.Duration {
+	// an idle bucket is full again after this time, so the state is useless
+	return time.Duration(float64(a.Period) * float64(a.burst()) / float64(a.Rate))
+}
+
+func (a TokenBucket) validate() error {
+	if a.Rate <= 0 || a.Period <= 0 {
+		return fmt.Errorf("token bucket requires positive rate and period, got %d per %s", a.Rate, a.Period)
+	}
+	return nil
+}
+
+func (a TokenBucket) burst() int64 {
+	if a.Burst > 0 {
+		return a.Burst
+	}
+	return a.Rate
+}
+
+// SlidingWindow allows Limit requests per Window, weighting the previous
+// fixed window by its overlap with the sliding one.
+type SlidingWindow struct {
+	Limit  int64
+	Window time.Duration
+}
+
+func (a SlidingWindow) Take(state *State, now time.Time) Result {
+	windowStart := now.Truncate(a.Window)
+	if !state.WindowStart.Equal(windowStart) {
+		if state.WindowStart.Equal(windowStart.Add(-a.Window)) {
+			state.PrevCount = state.Count
+		} else {
+			state.PrevCount = 0
+		}
+		state.Count = 0
+		state.WindowStart = windowStart
+	}
+	state.UpdatedAt = now
+
+	elapsed := now.Sub(windowStart)
+	weight := 1 - float64(elapsed)/float64(a.Window)
+	estimated := float64(state.PrevCount)*weight + float64(state.Count)
+
+	result := Result{Limit: a.Limit, Reset: a.Window - elapsed}
+	if estimated+1 <= float64(a.Limit) {
+		state.Count++
+		result.Allowed = true
+		result.Remaining = int64(float64(a.Limit) - estimated - 1)
+		return result
+	}
+
+	result.RetryAfter = result.Reset
+	if state.PrevCount > 0 && state.Count < a.Limit {
+		// the previous window fades out linearly, find when one slot frees up
+		neededWeight := float64(a.Limit-1-state.Count) / float64(state.PrevCount)
+		at := time.Duration((1 - neededWeight) * float64(a.Window))
+		if at > elapsed {
+			result.RetryAfter = at - elapsed
+		}
+	}
+
+	return result
+}
+
+func (a SlidingWindow) TTL() time.Duration {
+	return 2 * a.Window
+}
+
+func (a SlidingWindow) validate() error {
+	if a.Limit <= 0 || a.Window <= 0 {
+		return fmt.Errorf("sliding window requires positive limit and window, got %d per %s", a.Limit, a.Window)
+	}
+	return nil
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
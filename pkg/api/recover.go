@@ -6,6 +6,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/neonlabsorg/neon-service-framework/pkg/reporter"
 )
 
 type (
@@ -77,6 +78,8 @@ func (e *DefaultApiContextExtender) RecoverWithConfig(config RecoverConfig) echo
 
 			defer func() {
 				if r := recover(); r != nil {
+					reporter.ReportPanic(c.Request().Context(), e.reporter, r, RequestTags(c))
+
 					err, ok := r.(error)
 					if !ok {
 						err = fmt.Errorf("%v", r)
@@ -0,0 +1,279 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+)
+
+const (
+	TransportWebSocket = "websocket"
+	TransportSSE       = "sse"
+)
+
+// Message is delivered to all connections subscribed to its topic. Event is
+// used as the SSE event name and as the "event" field of WebSocket frames.
+type Message struct {
+	Topic string
+	Event string
+	Data  json.RawMessage
+}
+
+type Config struct {
+	// SendBufferSize is the number of messages queued per connection. A
+	// connection whose buffer is full is considered a slow consumer and closed.
+	// Optional. Default value 64.
+	SendBufferSize int
+
+	// PingInterval defines how often WebSocket pings and SSE heartbeats are sent.
+	// Optional. Default value 30 seconds.
+	PingInterval time.Duration
+
+	// PongTimeout defines how long a WebSocket connection may stay silent.
+	// Optional. Default value 2 * PingInterval.
+	PongTimeout time.Duration
+
+	// WriteTimeout bounds every write to a connection.
+	// Optional. Default value 10 seconds.
+	WriteTimeout time.Duration
+
+	// MaxMessageSize limits the size of messages read from WebSocket clients.
+	// Optional. Default value 4KB.
+	MaxMessageSize int64
+
+	// Authorize decides whether the client of the request may subscribe to the
+	// topic. Optional. By default all topics are allowed.
+	Authorize func(c echo.Context, topic string) bool
+}
+
+// Hub tracks the streaming connections and their topic subscriptions. All
+// connections are closed when the context passed to NewHub is done.
+type Hub struct {
+	cfg    Config
+	ctx    context.Context
+	logger logger.Logger
+
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+	topics  map[string]map[*client]struct{}
+	closed  bool
+}
+
+func NewHub(ctx context.Context, cfg Config, log logger.Logger) *Hub {
+	initMetrics()
+
+	if cfg.SendBufferSize == 0 {
+		cfg.SendBufferSize = 64
+	}
+	if cfg.PingInterval == 0 {
+		cfg.PingInterval = 30 * time.Second
+	}
+	if cfg.PongTimeout == 0 {
+		cfg.PongTimeout = 2 * cfg.PingInterval
+	}
+	if cfg.WriteTimeout == 0 {
+		cfg.WriteTimeout = 10 * time.Second
+	}
+	if cfg.MaxMessageSize == 0 {
+		cfg.MaxMessageSize = 4 << 10
+	}
+
+	h := &Hub{
+		cfg:     cfg,
+		ctx:     ctx,
+		logger:  log,
+		clients: make(map[*client]struct{}),
+		topics:  make(map[string]map[*client]struct{}),
+	}
+
+	go func() {
+		<-ctx.Done()
+		h.Close()
+	}()
+
+	return h
+}
+
+// Publish encodes v as JSON and broadcasts it to the topic.
+func (h *Hub) Publish(topic string, event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errors.Internal.Wrap(err, "can't encode stream message")
+	}
+
+	h.Broadcast(Message{Topic: topic, Event: event, Data: data})
+
+	return nil
+}
+
+// Broadcast queues the message on every subscriber without blocking.
+// Subscribers with a full buffer are evicted.
+func (h *Hub) Broadcast(msg Message) {
+	var slow []*client
+
+	h.mu.RLock()
+	for c := range h.topics[msg.Topic] {
+		select {
+		case c.send <- msg:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range slow {
+		evictedTotal.WithLabelValues(c.transport).Inc()
+		h.logger.Warn().Str("transport", c.transport).Str("topic", msg.Topic).Msg("evicting slow stream consumer")
+		h.unregister(c)
+	}
+}
+
+// Count returns the number of open connections.
+func (h *Hub) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// Close closes all connections and rejects new ones.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	clients := make([]*client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		h.unregister(c)
+	}
+}
+
+func (h *Hub) register(transport string) (*client, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, false
+	}
+
+	c := &client{
+		transport: transport,
+		send:      make(chan Message, h.cfg.SendBufferSize),
+		done:      make(chan struct{}),
+		topics:    make(map[string]struct{}),
+	}
+	h.clients[c] = struct{}{}
+	activeConnections.WithLabelValues(transport).Inc()
+
+	return c, true
+}
+
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	if _, ok := h.clients[c]; !ok {
+		h.mu.Unlock()
+		return
+	}
+	delete(h.clients, c)
+	for topic := range c.topics {
+		h.removeSubscriber(topic, c)
+	}
+	h.mu.Unlock()
+
+	activeConnections.WithLabelValues(c.transport).Dec()
+	close(c.done)
+}
+
+func (h *Hub) subscribe(ctx echo.Context, c *client, topic string) bool {
+	if topic == "" || (h.cfg.Authorize != nil && !h.cfg.Authorize(ctx, topic)) {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.clients[c]; !ok {
+		return false
+	}
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[*client]struct{})
+	}
+	h.topics[topic][c] = struct{}{}
+	c.topics[topic] = struct{}{}
+
+	return true
+}
+
+func (h *Hub) unsubscribe(c *client, topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(c.topics, topic)
+	h.removeSubscriber(topic, c)
+}
+
+func (h *Hub) removeSubscriber(topic string, c *client) {
+	delete(h.topics[topic], c)
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+type client struct {
+	transport string
+	send      chan Message
+	done      chan struct{}
+	topics    map[string]struct{}
+}
+
+// Routes is the set of the routes of the streaming endpoints. It is used to
+// skip middleware which breaks long-lived connections, e.g. as the Skipper of
+// the request logger. The routes are matched rather than the request headers,
+// so that clients can't skip the middleware of other routes.
+type Routes struct {
+	mu    sync.RWMutex
+	paths map[string]struct{}
+}
+
+// Add registers the route path, e.g. "/stream" or "/stream/events".
+func (r *Routes) Add(paths ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.paths == nil {
+		r.paths = make(map[string]struct{})
+	}
+	for _, path := range paths {
+		r.paths[path] = struct{}{}
+	}
+}
+
+// IsStreamingRoute reports whether the request is routed to a registered
+// streaming endpoint.
+func (r *Routes) IsStreamingRoute(c echo.Context) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.paths[c.Path()]
+	return ok
+}
+
+func requestTopics(c echo.Context) []string {
+	var topics []string
+	for _, value := range c.QueryParams()["topic"] {
+		for _, topic := range strings.Split(value, ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				topics = append(topics, topic)
+			}
+		}
+	}
+	return topics
+}
@@ -0,0 +1,27 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	metricsOnce sync.Once
+
+	activeConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "stream_active_connections",
+		Help: "Open streaming connections by transport.",
+	}, []string{"transport"})
+
+	evictedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "stream_evicted_connections_total",
+		Help: "Streaming connections closed as slow consumers by transport.",
+	}, []string{"transport"})
+)
+
+func initMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(activeConnections, evictedTotal)
+	})
+}
@@ -0,0 +1,73 @@
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SSEHandler streams the messages of the topics given in the "topic" query
+// parameters as Server-Sent Events.
+func (h *Hub) SSEHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		cl, ok := h.register(TransportSSE)
+		if !ok {
+			return c.NoContent(http.StatusServiceUnavailable)
+		}
+		defer h.unregister(cl)
+
+		for _, topic := range requestTopics(c) {
+			h.subscribe(c, cl, topic)
+		}
+
+		resp := c.Response()
+		resp.Header().Set(echo.HeaderContentType, "text/event-stream")
+		resp.Header().Set("Cache-Control", "no-cache")
+		resp.Header().Set("Connection", "keep-alive")
+		resp.Header().Set("X-Accel-Buffering", "no")
+		resp.WriteHeader(http.StatusOK)
+		resp.Flush()
+
+		controller := http.NewResponseController(resp.Writer)
+		heartbeat := time.NewTicker(h.cfg.PingInterval)
+		defer heartbeat.Stop()
+
+		for {
+			var payload []byte
+			select {
+			case <-c.Request().Context().Done():
+				return nil
+			case <-cl.done:
+				return nil
+			case msg := <-cl.send:
+				payload = encodeEvent(msg)
+			case <-heartbeat.C:
+				payload = []byte(": ping\n\n")
+			}
+
+			_ = controller.SetWriteDeadline(time.Now().Add(h.cfg.WriteTimeout))
+			if _, err := resp.Write(payload); err != nil {
+				return nil
+			}
+			resp.Flush()
+		}
+	}
+}
+
+func encodeEvent(msg Message) []byte {
+	var buf bytes.Buffer
+	if msg.Event != "" {
+		fmt.Fprintf(&buf, "event: %s\n", msg.Event)
+	}
+	for _, line := range bytes.Split(msg.Data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
+}
@@ -0,0 +1,151 @@
+package stream
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+// clientCommand is sent by WebSocket clients to change subscriptions:
+// {"action": "subscribe", "topic": "slots"}.
+type clientCommand struct {
+	Action string `json:"action"`
+	Topic  string `json:"topic"`
+}
+
+type frame struct {
+	Topic string          `json:"topic"`
+	Event string          `json:"event,omitempty"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// WebSocketHandler upgrades the request and streams the messages of the
+// topics given in the "topic" query parameters and subscribed later by
+// client commands.
+func (h *Hub) WebSocketHandler(upgrader websocket.Upgrader) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+		if err != nil {
+			// the upgrader has already written the error response
+			return nil
+		}
+
+		cl, ok := h.register(TransportWebSocket)
+		if !ok {
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server is shutting down"), time.Now().Add(h.cfg.WriteTimeout))
+			return conn.Close()
+		}
+
+		for _, topic := range requestTopics(c) {
+			h.subscribe(c, cl, topic)
+		}
+
+		// the reader uses the context, which echo reuses once the handler
+		// returns, so the handler waits for it. The writer closes the
+		// connection when it stops, which stops the reader.
+		readerDone := make(chan struct{})
+		go func() {
+			defer close(readerDone)
+			h.readWebSocket(c, cl, conn)
+		}()
+		h.writeWebSocket(cl, conn)
+		<-readerDone
+
+		return nil
+	}
+}
+
+func (h *Hub) readWebSocket(c echo.Context, cl *client, conn *websocket.Conn) {
+	defer h.unregister(cl)
+
+	conn.SetReadLimit(h.cfg.MaxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(h.cfg.PongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(h.cfg.PongTimeout))
+	})
+
+	for {
+		var cmd clientCommand
+		if err := conn.ReadJSON(&cmd); err != nil {
+			switch err.(type) {
+			case *json.SyntaxError, *json.UnmarshalTypeError:
+				h.replyError(cl, "invalid command")
+				continue
+			}
+			return
+		}
+
+		switch cmd.Action {
+		case "subscribe":
+			h.subscribe(c, cl, cmd.Topic)
+		case "unsubscribe":
+			h.unsubscribe(cl, cmd.Topic)
+		}
+	}
+}
+
+// replyError queues an error frame, {"topic": "", "event": "error", "data":
+// {"message": "invalid command"}}, for the client. It is dropped if the send
+// buffer is full.
+func (h *Hub) replyError(cl *client, message string) {
+	data, _ := json.Marshal(map[string]string{"message": message})
+	select {
+	case cl.send <- Message{Event: "error", Data: data}:
+	default:
+	}
+}
+
+func (h *Hub) writeWebSocket(cl *client, conn *websocket.Conn) {
+	ping := time.NewTicker(h.cfg.PingInterval)
+	defer func() {
+		ping.Stop()
+		h.unregister(cl)
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(h.cfg.WriteTimeout))
+		_ = conn.Close()
+	}()
+
+	for {
+		select {
+		case <-cl.done:
+			return
+		case msg := <-cl.send:
+			_ = conn.SetWriteDeadline(time.Now().Add(h.cfg.WriteTimeout))
+			if err := conn.WriteJSON(frame{Topic: msg.Topic, Event: msg.Event, Data: msg.Data}); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(h.cfg.WriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// NewUpgrader returns an upgrader accepting the given origins. Without origins
+// only same origin requests are accepted, "*" accepts any origin.
+func NewUpgrader(origins ...string) websocket.Upgrader {
+	var checkOrigin func(r *http.Request) bool
+	if len(origins) > 0 {
+		checkOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			for _, allowed := range origins {
+				if allowed == "*" || origin == allowed {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	return websocket.Upgrader{
+		// nil is the same origin check of gorilla
+		CheckOrigin: checkOrigin,
+		Error: func(w http.ResponseWriter, r *http.Request, status int, reason error) {
+			http.Error(w, errors.Wrap(reason, "websocket upgrade failed").Error(), status)
+		},
+	}
+}
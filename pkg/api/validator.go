@@ -1,27 +1,40 @@
 package api
 
 import (
+	"context"
 	"reflect"
+	"strings"
 
 	"gopkg.in/go-playground/validator.v9"
 )
 
 type Validator struct {
 	validator *validator.Validate
+	messages  *Messages
 }
 
 func (v *Validator) Validate(i interface{}) error {
-	err := v.validator.Struct(i)
+	return v.ValidateCtx(context.Background(), i)
+}
+
+// ValidateCtx validates the struct passing the context to the validations
+// registered with RegisterValidationCtx and RegisterStructValidation.
+func (v *Validator) ValidateCtx(ctx context.Context, i interface{}) error {
+	err := v.validator.StructCtx(ctx, i)
 	if err != nil {
-		return NewValidatorError(err, i)
+		return &ValidateError{Veer: err, Model: i, messages: v.messages}
 	}
 	return nil
 }
 
 func (v *Validator) Var(i interface{}, tag string) error {
-	err := v.validator.Var(i, tag)
+	return v.VarCtx(context.Background(), i, tag)
+}
+
+func (v *Validator) VarCtx(ctx context.Context, i interface{}, tag string) error {
+	err := v.validator.VarCtx(ctx, i, tag)
 	if err != nil {
-		return NewValidatorError(err, nil)
+		return &ValidateError{Veer: err, messages: v.messages}
 	}
 	return nil
 }
@@ -32,21 +45,126 @@ func (v *Validator) RegisterValidation(tag string, fn Func, callValidationEvenIf
 	}, callValidationEvenIfNull...)
 }
 
+// RegisterValidationCtx registers a validation which needs the context, e.g.
+// to look up the value in a database.
+func (v *Validator) RegisterValidationCtx(tag string, fn FuncCtx, callValidationEvenIfNull ...bool) error {
+	return v.validator.RegisterValidationCtx(tag, func(ctx context.Context, fl validator.FieldLevel) bool {
+		return fn(ctx, fl.Field())
+	}, callValidationEvenIfNull...)
+}
+
+// RegisterValidationWithMessage registers the validation together with its
+// message template in the default locale.
+func (v *Validator) RegisterValidationWithMessage(tag string, fn Func, template string, callValidationEvenIfNull ...bool) error {
+	if err := v.RegisterValidation(tag, fn, callValidationEvenIfNull...); err != nil {
+		return err
+	}
+	v.messages.Register(v.messages.defaultLocale, tag, template)
+	return nil
+}
+
+// RegisterMessage sets the message template of the tag for the locale.
+func (v *Validator) RegisterMessage(locale string, tag string, template string) {
+	v.messages.Register(locale, tag, template)
+}
+
+func (v *Validator) SetDefaultLocale(locale string) {
+	v.messages.SetDefaultLocale(locale)
+}
+
+func (v *Validator) GetMessages() *Messages {
+	return v.messages
+}
+
 type Func func(value reflect.Value) bool
 
+type FuncCtx func(ctx context.Context, value reflect.Value) bool
+
 func NewValidator() *Validator {
-	return &Validator{validator: validator.New()}
+	v := validator.New()
+	v.RegisterTagNameFunc(fieldTagName)
+	registerFileValidations(v)
+	registerNumberValidations(v)
+
+	instance := &Validator{validator: v, messages: NewMessages()}
+	registerBlockchainValidations(instance)
+
+	return instance
 }
 
 func NewValidatorError(err error, model interface{}) error {
-	return &ValidateError{err, model}
+	return &ValidateError{Veer: err, Model: model}
 }
 
 type ValidateError struct {
-	Veer  error
-	Model interface{}
+	Veer     error
+	Model    interface{}
+	messages *Messages
+}
+
+// Fields describes the validation error per field with the messages of the
+// locale. The field names are JSON paths like "items[3].amount".
+func (e *ValidateError) Fields(locale string) (message string, fields []ValidationErrorFieldModel) {
+	message = "Request is invalid"
+	fields = []ValidationErrorFieldModel{}
+
+	verrs, ok := e.Veer.(validator.ValidationErrors)
+	if !ok {
+		return e.Veer.Error(), fields
+	}
+
+	messages := e.Messages()
+	if locale == "" {
+		locale = messages.defaultLocale
+	}
+
+	for _, fe := range verrs {
+		name := fieldPath(fe.Namespace())
+		param := fieldParam(e.Model, fe)
+		fields = append(fields, ValidationErrorFieldModel{
+			FieldName: name,
+			Namespace: fe.StructNamespace(),
+			Tag:       fe.Tag(),
+			TagParam:  param,
+			Message:   messages.Format(locale, fe, name, param),
+		})
+	}
+
+	return message, fields
+}
+
+// Messages returns the message templates of the validator which produced
+// the error.
+func (e *ValidateError) Messages() *Messages {
+	if e.messages == nil {
+		return defaultMessages
+	}
+	return e.messages
 }
 
 func (e *ValidateError) Error() string {
 	return e.Veer.Error()
 }
+
+// fieldTagName names the fields in errors as they appear in requests: by the
+// json tag, then by the binder tags.
+func fieldTagName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "query", "param", "header", "cookie"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// fieldPath strips the name of the validated struct from the namespace.
+func fieldPath(namespace string) string {
+	if idx := strings.IndexAny(namespace, ".["); idx >= 0 && namespace[idx] == '.' {
+		return namespace[idx+1:]
+	}
+	return namespace
+}
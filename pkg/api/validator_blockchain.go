@@ -0,0 +1,97 @@
+package api
+
+import (
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/neonlabsorg/neon-service-framework/pkg/eth"
+)
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// registerBlockchainValidations adds the tags for Solana and Ethereum values,
+// either in their string encodings or as the typed values.
+func registerBlockchainValidations(v *Validator) {
+	_ = v.RegisterValidation("solana_pubkey", typedValidation(reflect.TypeOf(solana.PublicKey{}), func(s string) bool {
+		_, err := solana.PublicKeyFromBase58(s)
+		return err == nil
+	}))
+	_ = v.RegisterValidation("solana_signature", typedValidation(reflect.TypeOf(solana.Signature{}), func(s string) bool {
+		_, err := solana.SignatureFromBase58(s)
+		return err == nil
+	}))
+	// mixed case addresses must have a valid checksum, lower and upper case
+	// ones are accepted as is
+	_ = v.RegisterValidation("eth_address", typedValidation(reflect.TypeOf(eth.Address{}), func(s string) bool {
+		_, err := eth.ParseAddress(s)
+		return err == nil
+	}))
+	_ = v.RegisterValidation("eth_address_checksum", stringValidation(eth.IsChecksumAddress))
+	_ = v.RegisterValidation("eth_hash", stringValidation(func(s string) bool {
+		return eth.IsHex(s) && len(s) == 66
+	}))
+	_ = v.RegisterValidation("hex_quantity", stringValidation(isHexQuantity))
+	_ = v.RegisterValidation("uint256", isUint256)
+}
+
+// typedValidation accepts the values of the type t as they are valid by
+// construction and validates the strings with fn.
+func typedValidation(t reflect.Type, fn func(s string) bool) Func {
+	validate := stringValidation(fn)
+	return func(value reflect.Value) bool {
+		if value.Type() == t {
+			return true
+		}
+		return validate(value)
+	}
+}
+
+func stringValidation(fn func(s string) bool) Func {
+	return func(value reflect.Value) bool {
+		if value.Kind() != reflect.String {
+			return false
+		}
+		return fn(value.String())
+	}
+}
+
+// isHexQuantity checks the encoding of quantities in the Ethereum JSON-RPC:
+// 0x prefixed hex without leading zeros.
+func isHexQuantity(s string) bool {
+	if !eth.Has0xPrefix(s) || len(s) < 3 || len(s) > 66 {
+		return false
+	}
+	digits := s[2:]
+	if len(digits) > 1 && digits[0] == '0' {
+		return false
+	}
+	return strings.Trim(strings.ToLower(digits), "0123456789abcdef") == ""
+}
+
+// isUint256 accepts decimal and 0x prefixed hex strings and integers in the
+// range of uint256. big.Int and decimal.Decimal fields arrive here as their
+// decimal strings, see bigNumber.
+func isUint256(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() >= 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.String:
+	default:
+		return false
+	}
+
+	s := value.String()
+	n := new(big.Int)
+	var ok bool
+	if eth.Has0xPrefix(s) {
+		_, ok = n.SetString(s[2:], 16)
+	} else {
+		_, ok = n.SetString(s, 10)
+	}
+
+	return ok && n.Sign() >= 0 && n.Cmp(maxUint256) <= 0
+}
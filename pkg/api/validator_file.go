@@ -0,0 +1,108 @@
+package api
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/labstack/gommon/bytes"
+	"github.com/neonlabsorg/neon-service-framework/pkg/echo/binder"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// fileAttributes replaces uploaded files during validation. The validator
+// ignores the tags of struct fields, so the files are presented as maps.
+type fileAttributes map[string]interface{}
+
+const (
+	fileAttributeSize         = "size"
+	fileAttributeContentTypes = "content_types"
+)
+
+// registerFileValidations adds the max_size and mime tags for
+// *multipart.FileHeader and *binder.File fields, e.g.
+// `validate:"required,max_size=10MB,mime=image/png image/*"`.
+func registerFileValidations(v *validator.Validate) {
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		header := field.Interface().(multipart.FileHeader)
+		return fileAttributes{
+			fileAttributeSize:         header.Size,
+			fileAttributeContentTypes: []string{sniffFileHeader(&header), header.Header.Get("Content-Type")},
+		}
+	}, multipart.FileHeader{})
+
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		file := field.Interface().(binder.File)
+		return fileAttributes{
+			fileAttributeSize:         file.Size,
+			fileAttributeContentTypes: []string{file.ContentType, file.DeclaredContentType},
+		}
+	}, binder.File{})
+
+	_ = v.RegisterValidation("max_size", func(fl validator.FieldLevel) bool {
+		attributes, ok := fl.Field().Interface().(fileAttributes)
+		if !ok {
+			return false
+		}
+		limit, err := bytes.Parse(fl.Param())
+		if err != nil {
+			return false
+		}
+		return attributes[fileAttributeSize].(int64) <= limit
+	})
+
+	_ = v.RegisterValidation("mime", func(fl validator.FieldLevel) bool {
+		attributes, ok := fl.Field().Interface().(fileAttributes)
+		if !ok {
+			return false
+		}
+		return matchContentType(attributes[fileAttributeContentTypes].([]string), strings.Fields(fl.Param()))
+	})
+}
+
+// matchContentType checks the detected content type of the file. The declared
+// one is only used when detection gives a generic type.
+func matchContentType(contentTypes []string, allowed []string) bool {
+	detected, declared := mediaType(contentTypes[0]), mediaType(contentTypes[1])
+
+	candidates := []string{detected}
+	if detected == "" || detected == "application/octet-stream" || detected == "text/plain" {
+		candidates = append(candidates, declared)
+	}
+
+	for _, candidate := range candidates {
+		for _, pattern := range allowed {
+			if candidate == "" {
+				continue
+			}
+			if pattern == candidate || strings.HasSuffix(pattern, "/*") && strings.HasPrefix(candidate, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+func sniffFileHeader(header *multipart.FileHeader) string {
+	f, err := header.Open()
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	return http.DetectContentType(head[:n])
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/go-playground/validator.v9"
+)
+
+const DefaultLocale = "en"
+
+// Messages holds the validation message templates by locale and tag. The
+// templates may use the {field}, {tag}, {param} and {value} placeholders.
+// A template registered for "tag.string", "tag.number" or "tag.items" is
+// preferred for fields of that kind.
+type Messages struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	templates     map[string]map[string]string
+}
+
+var defaultMessages = NewMessages()
+
+var englishMessages = map[string]string{
+	"required":             "'{field}' is required",
+	"min.string":           "'{field}' must be at least {param} characters long",
+	"min.items":            "'{field}' must contain at least {param} items",
+	"min":                  "'{field}' must be at least {param}",
+	"max.string":           "'{field}' must be at most {param} characters long",
+	"max.items":            "'{field}' must contain at most {param} items",
+	"max":                  "'{field}' must be at most {param}",
+	"len.string":           "'{field}' must be exactly {param} characters long",
+	"len.items":            "'{field}' must contain exactly {param} items",
+	"len":                  "'{field}' must be equal to {param}",
+	"eq":                   "'{field}' must be equal to {param}",
+	"ne":                   "'{field}' must not be equal to {param}",
+	"gt":                   "'{field}' must be greater than {param}",
+	"gte":                  "'{field}' must be greater than or equal to {param}",
+	"lt":                   "'{field}' must be less than {param}",
+	"lte":                  "'{field}' must be less than or equal to {param}",
+	"oneof":                "'{field}' must be one of [{param}]",
+	"email":                "'{field}' must be a valid email address",
+	"url":                  "'{field}' must be a valid URL",
+	"uri":                  "'{field}' must be a valid URI",
+	"uuid":                 "'{field}' must be a valid UUID",
+	"numeric":              "'{field}' must be numeric",
+	"number":               "'{field}' must be a number",
+	"alpha":                "'{field}' must contain letters only",
+	"alphanum":             "'{field}' must contain letters and digits only",
+	"hexadecimal":          "'{field}' must be hexadecimal",
+	"ip":                   "'{field}' must be a valid IP address",
+	"ipv4":                 "'{field}' must be a valid IPv4 address",
+	"ipv6":                 "'{field}' must be a valid IPv6 address",
+	"contains":             "'{field}' must contain '{param}'",
+	"excludes":             "'{field}' must not contain '{param}'",
+	"startswith":           "'{field}' must start with '{param}'",
+	"endswith":             "'{field}' must end with '{param}'",
+	"unique":               "'{field}' must contain unique values",
+	"max_size":             "'{field}' must not be larger than {param}",
+	"mime":                 "'{field}' must be of type {param}",
+	"eqfield":              "'{field}' must be equal to '{param}'",
+	"nefield":              "'{field}' must not be equal to '{param}'",
+	"gtfield":              "'{field}' must be greater than '{param}'",
+	"gtefield":             "'{field}' must be greater than or equal to '{param}'",
+	"ltfield":              "'{field}' must be less than '{param}'",
+	"ltefield":             "'{field}' must be less than or equal to '{param}'",
+	"eqcsfield":            "'{field}' must be equal to '{param}'",
+	"necsfield":            "'{field}' must not be equal to '{param}'",
+	"gtcsfield":            "'{field}' must be greater than '{param}'",
+	"gtecsfield":           "'{field}' must be greater than or equal to '{param}'",
+	"ltcsfield":            "'{field}' must be less than '{param}'",
+	"ltecsfield":           "'{field}' must be less than or equal to '{param}'",
+	"required_with":        "'{field}' is required when '{param}' is present",
+	"required_with_all":    "'{field}' is required when all of '{param}' are present",
+	"required_without":     "'{field}' is required when '{param}' is missing",
+	"required_without_all": "'{field}' is required when all of '{param}' are missing",
+
+	"solana_pubkey":        "'{field}' must be a base58 Solana public key",
+	"solana_signature":     "'{field}' must be a base58 Solana transaction signature",
+	"eth_address":          "'{field}' must be a 0x prefixed Ethereum address",
+	"eth_address_checksum": "'{field}' must be an EIP-55 checksum Ethereum address",
+	"eth_hash":             "'{field}' must be a 0x prefixed 32 byte hex hash",
+	"hex_quantity":         "'{field}' must be a 0x prefixed hex quantity without leading zeros",
+	"uint256":              "'{field}' must be an unsigned 256 bit integer",
+}
+
+func NewMessages() *Messages {
+	m := &Messages{
+		defaultLocale: DefaultLocale,
+		templates:     make(map[string]map[string]string),
+	}
+	for tag, template := range englishMessages {
+		m.Register(DefaultLocale, tag, template)
+	}
+	return m
+}
+
+// Register sets the template of the tag for the locale, e.g. "de" or "pt-br".
+func (m *Messages) Register(locale string, tag string, template string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	locale = strings.ToLower(locale)
+	if m.templates[locale] == nil {
+		m.templates[locale] = make(map[string]string)
+	}
+	m.templates[locale][tag] = template
+}
+
+// SetDefaultLocale sets the locale used when the request asks for none of
+// the registered ones.
+func (m *Messages) SetDefaultLocale(locale string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaultLocale = strings.ToLower(locale)
+}
+
+// Locale picks the registered locale best matching an Accept-Language header.
+func (m *Messages) Locale(acceptLanguage string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if _, ok := m.templates[tag]; ok {
+			return tag
+		}
+		if base, _, ok := strings.Cut(tag, "-"); ok {
+			if _, ok := m.templates[base]; ok {
+				return base
+			}
+		}
+	}
+
+	return m.defaultLocale
+}
+
+// Format renders the message of the field error in the locale, falling back
+// to the default locale and then to a generic message. The param replaces the
+// param of the tag, e.g. to name the fields of cross-field tags by their JSON
+// paths.
+func (m *Messages) Format(locale string, fe validator.FieldError, field string, param string) string {
+	m.mu.RLock()
+	template, ok := m.lookup(strings.ToLower(locale), fe)
+	if !ok {
+		template, ok = m.lookup(m.defaultLocale, fe)
+	}
+	m.mu.RUnlock()
+
+	if !ok {
+		if param != "" {
+			return fmt.Sprintf(`Invalid field '%s' by tag '%s = %s'`, field, fe.Tag(), param)
+		}
+		return fmt.Sprintf(`Invalid field '%s' by tag '%s'`, field, fe.Tag())
+	}
+
+	return strings.NewReplacer(
+		"{field}", field,
+		"{tag}", fe.Tag(),
+		"{param}", param,
+		"{value}", fmt.Sprint(fe.Value()),
+	).Replace(template)
+}
+
+func (m *Messages) lookup(locale string, fe validator.FieldError) (string, bool) {
+	templates, ok := m.templates[locale]
+	if !ok {
+		return "", false
+	}
+	if class := fieldClass(fe); class != "" {
+		if template, ok := templates[fe.Tag()+"."+class]; ok {
+			return template, true
+		}
+	}
+	template, ok := templates[fe.Tag()]
+	return template, ok
+}
+
+func fieldClass(fe validator.FieldError) string {
+	if fe.Type() == bigNumberType {
+		return "number"
+	}
+	return kindClass(fe.Kind())
+}
+
+func kindClass(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return ""
+}
+
+// parseAcceptLanguage returns the lower case language tags of the header
+// ordered by quality.
+func parseAcceptLanguage(header string) []string {
+	type language struct {
+		tag     string
+		quality float64
+	}
+
+	var languages []language
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			languages = append(languages, language{tag: strings.ToLower(tag), quality: quality})
+		}
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].quality > languages[j].quality
+	})
+
+	tags := make([]string, len(languages))
+	for i, l := range languages {
+		tags[i] = l.tag
+	}
+	return tags
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/shopspring/decimal"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// bigNumber replaces big.Int and decimal.Decimal values during validation.
+// The validator ignores the tags of struct fields, so the numbers are
+// presented by their decimal string, e.g. to the uint256 tag.
+type bigNumber string
+
+var bigNumberType = reflect.TypeOf(bigNumber(""))
+
+// numberComparisons are the tags which compare the length of strings. They
+// compare bigNumber values by their numeric value instead.
+var numberComparisons = map[string]func(cmp int) bool{
+	"eq":  func(cmp int) bool { return cmp == 0 },
+	"ne":  func(cmp int) bool { return cmp != 0 },
+	"gt":  func(cmp int) bool { return cmp > 0 },
+	"gte": func(cmp int) bool { return cmp >= 0 },
+	"min": func(cmp int) bool { return cmp >= 0 },
+	"lt":  func(cmp int) bool { return cmp < 0 },
+	"lte": func(cmp int) bool { return cmp <= 0 },
+	"max": func(cmp int) bool { return cmp <= 0 },
+}
+
+// registerNumberValidations presents big.Int and decimal.Decimal fields as
+// bigNumber and replaces the comparison tags, e.g.
+// `validate:"required,gt=0,lte=1000000000000000000000"`. Other values are
+// passed to the built-in comparisons.
+func registerNumberValidations(v *validator.Validate) {
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		n := field.Interface().(big.Int)
+		return bigNumber(n.String())
+	}, big.Int{})
+
+	v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		return bigNumber(field.Interface().(decimal.Decimal).String())
+	}, decimal.Decimal{})
+
+	builtin := validator.New()
+	for tag, accept := range numberComparisons {
+		tag, accept := tag, accept
+		_ = v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			if fl.Field().Type() != bigNumberType {
+				return builtinComparison(builtin, fl, tag)
+			}
+
+			value, ok := new(big.Rat).SetString(fl.Field().String())
+			if !ok {
+				return false
+			}
+			param, ok := new(big.Rat).SetString(fl.Param())
+			if !ok {
+				panic("invalid number parameter of the " + tag + " tag: " + fl.Param())
+			}
+			return accept(value.Cmp(param))
+		})
+	}
+}
+
+func builtinComparison(builtin *validator.Validate, fl validator.FieldLevel, tag string) bool {
+	if fl.Param() != "" {
+		tag += "=" + fl.Param()
+	}
+	return builtin.Var(fl.Field().Interface(), tag) == nil
+}
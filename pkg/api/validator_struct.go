@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"gopkg.in/go-playground/validator.v9"
+)
+
+// StructFunc validates a whole struct, e.g. rules spanning several fields.
+// Errors are reported through the StructLevel.
+type StructFunc func(ctx context.Context, sl StructLevel)
+
+// StructLevel gives struct validations access to the validated struct.
+type StructLevel struct {
+	sl validator.StructLevel
+}
+
+// Current returns the validated struct.
+func (s StructLevel) Current() reflect.Value {
+	return s.sl.Current()
+}
+
+// Top returns the top level struct of the validation.
+func (s StructLevel) Top() reflect.Value {
+	return s.sl.Top()
+}
+
+// ReportError reports that the field, given by its Go name, failed the tag.
+// The error is rendered like the errors of field tags.
+func (s StructLevel) ReportError(field string, tag string, param string) {
+	current := s.sl.Current()
+	structField, ok := current.Type().FieldByName(field)
+	if !ok {
+		s.sl.ReportError(nil, field, field, tag, param)
+		return
+	}
+
+	name := fieldTagName(structField)
+	if name == "" {
+		name = field
+	}
+	s.sl.ReportError(current.FieldByIndex(structField.Index).Interface(), name, field, tag, param)
+}
+
+// RegisterStructValidation registers the validation for the types of the
+// given values. It runs after the field tags of the struct are validated.
+func (v *Validator) RegisterStructValidation(fn StructFunc, types ...interface{}) {
+	v.validator.RegisterStructValidationCtx(func(ctx context.Context, sl validator.StructLevel) {
+		fn(ctx, StructLevel{sl: sl})
+	}, types...)
+}
+
+// crossFieldTags take the names of other fields as their param. Fields of
+// the "cs" tags are relative to the top level struct, the others are siblings.
+var crossFieldTags = map[string]bool{
+	"eqfield": true, "nefield": true, "gtfield": true, "gtefield": true, "ltfield": true, "ltefield": true,
+	"eqcsfield": true, "necsfield": true, "gtcsfield": true, "gtecsfield": true, "ltcsfield": true, "ltecsfield": true,
+	"required_with": true, "required_with_all": true, "required_without": true, "required_without_all": true,
+}
+
+// fieldParam names the fields in the param of cross-field tags by their JSON
+// paths, the params of other tags are returned as is.
+func fieldParam(model interface{}, fe validator.FieldError) string {
+	if !crossFieldTags[fe.Tag()] || model == nil {
+		return fe.Param()
+	}
+
+	root := reflect.TypeOf(model)
+	if strings.Contains(fe.Tag(), "csfield") {
+		if path, ok := jsonPath(root, strings.Split(fe.Param(), ".")); ok {
+			return path
+		}
+		return fe.Param()
+	}
+
+	// the parent of the field is walked by the Go namespace while the prefix
+	// of the JSON path keeps the indexes of slices and maps
+	goSegments := strings.Split(fe.StructNamespace(), ".")
+	jsonPrefix := ""
+	if path := fieldPath(fe.Namespace()); strings.Contains(path, ".") {
+		jsonPrefix = path[:strings.LastIndex(path, ".")+1]
+	}
+	if len(goSegments) < 2 {
+		return fe.Param()
+	}
+	parent, ok := walkType(root, goSegments[1:len(goSegments)-1])
+	if !ok {
+		return fe.Param()
+	}
+
+	params := strings.Fields(fe.Param())
+	for i, param := range params {
+		if name, ok := jsonPath(parent, []string{param}); ok {
+			params[i] = jsonPrefix + name
+		}
+	}
+	return strings.Join(params, " ")
+}
+
+// walkType follows the Go field names from the struct type, skipping the
+// indexes of slices and maps.
+func walkType(t reflect.Type, segments []string) (reflect.Type, bool) {
+	for _, segment := range segments {
+		if idx := strings.IndexByte(segment, '['); idx >= 0 {
+			segment = segment[:idx]
+		}
+		t = elemType(t)
+		field, ok := t.FieldByName(segment)
+		if !ok {
+			return nil, false
+		}
+		t = field.Type
+		for k := t.Kind(); k == reflect.Ptr || k == reflect.Slice || k == reflect.Array || k == reflect.Map; k = t.Kind() {
+			t = t.Elem()
+		}
+	}
+	return elemType(t), true
+}
+
+// jsonPath converts the Go field names to the JSON names.
+func jsonPath(t reflect.Type, segments []string) (string, bool) {
+	names := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		t = elemType(t)
+		if t.Kind() != reflect.Struct {
+			return "", false
+		}
+		field, ok := t.FieldByName(segment)
+		if !ok {
+			return "", false
+		}
+		name := fieldTagName(field)
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+		t = field.Type
+	}
+	return strings.Join(names, "."), true
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	sqlDriver "database/sql/driver"
+	stdErrors "errors"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+// clickhouseTypes maps ClickHouse exception codes to error types.
+var clickhouseTypes = map[int32]errors.ErrorType{
+	57:  errors.Logical,     // TABLE_ALREADY_EXISTS
+	82:  errors.Logical,     // DATABASE_ALREADY_EXISTS
+	469: errors.Logical,     // VIOLATED_CONSTRAINT
+	159: errors.Temporarily, // TIMEOUT_EXCEEDED
+	164: errors.Temporarily, // READONLY
+	202: errors.Temporarily, // TOO_MANY_SIMULTANEOUS_QUERIES
+	209: errors.Temporarily, // SOCKET_TIMEOUT
+	210: errors.Temporarily, // NETWORK_ERROR
+	225: errors.Temporarily, // NO_ZOOKEEPER
+	236: errors.Temporarily, // ABORTED
+	241: errors.Temporarily, // MEMORY_LIMIT_EXCEEDED
+	242: errors.Temporarily, // TABLE_IS_READ_ONLY
+	252: errors.Temporarily, // TOO_MANY_PARTS
+	279: errors.Temporarily, // ALL_CONNECTION_TRIES_FAILED
+	319: errors.Temporarily, // UNKNOWN_STATUS_OF_INSERT
+	425: errors.Temporarily, // SYSTEM_ERROR
+	999: errors.Temporarily, // KEEPER_EXCEPTION
+}
+
+// TranslateClickhouse converts ClickHouse driver errors to errors.Error the
+// way TranslatePostgres does. The exception code and name are set in context.
+func TranslateClickhouse(err error) error {
+	if untranslated(err) {
+		return err
+	}
+	if translated, ok := translateClickhouse(err); ok {
+		return translated
+	}
+	if isConnectionLoss(err) {
+		return errors.Temporarily.Wrap(err, "clickhouse connection failed")
+	}
+	return errors.Internal.Wrap(err, "clickhouse error")
+}
+
+func translateClickhouse(err error) (error, bool) {
+	if stdErrors.Is(err, sql.ErrNoRows) {
+		return errors.NotFound.Wrap(err, "clickhouse"), true
+	}
+	if stdErrors.Is(err, clickhouse.ErrAcquireConnTimeout) || stdErrors.Is(err, sqlDriver.ErrBadConn) {
+		return errors.Temporarily.Wrap(err, "clickhouse connection failed"), true
+	}
+
+	var exception *clickhouse.Exception
+	if stdErrors.As(err, &exception) {
+		errorType, ok := clickhouseTypes[exception.Code]
+		if !ok {
+			errorType = errors.Internal
+		}
+
+		translated := errorType.Wrap(err, "clickhouse")
+		translated.AddToContext("exception_code", exception.Code)
+		if exception.Name != "" {
+			translated.AddToContext("exception", exception.Name)
+		}
+		return translated, true
+	}
+
+	return nil, false
+}
+
+// ClickhouseConn translates the errors of the queries and batches run on the
+// connection with TranslateClickhouse.
+type ClickhouseConn struct {
+	driver.Conn
+}
+
+func NewClickhouseConn(conn driver.Conn) *ClickhouseConn {
+	return &ClickhouseConn{Conn: conn}
+}
+
+func (c *ClickhouseConn) Select(ctx context.Context, dest any, query string, args ...any) error {
+	return TranslateClickhouse(c.Conn.Select(ctx, dest, query, args...))
+}
+
+func (c *ClickhouseConn) Query(ctx context.Context, query string, args ...any) (driver.Rows, error) {
+	rows, err := c.Conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, TranslateClickhouse(err)
+	}
+	return clickhouseRows{Rows: rows}, nil
+}
+
+func (c *ClickhouseConn) QueryRow(ctx context.Context, query string, args ...any) driver.Row {
+	return clickhouseRow{Row: c.Conn.QueryRow(ctx, query, args...)}
+}
+
+func (c *ClickhouseConn) PrepareBatch(ctx context.Context, query string) (driver.Batch, error) {
+	batch, err := c.Conn.PrepareBatch(ctx, query)
+	if err != nil {
+		return nil, TranslateClickhouse(err)
+	}
+	return clickhouseBatch{Batch: batch}, nil
+}
+
+func (c *ClickhouseConn) Exec(ctx context.Context, query string, args ...any) error {
+	return TranslateClickhouse(c.Conn.Exec(ctx, query, args...))
+}
+
+func (c *ClickhouseConn) AsyncInsert(ctx context.Context, query string, wait bool) error {
+	return TranslateClickhouse(c.Conn.AsyncInsert(ctx, query, wait))
+}
+
+func (c *ClickhouseConn) Ping(ctx context.Context) error {
+	return TranslateClickhouse(c.Conn.Ping(ctx))
+}
+
+type clickhouseRow struct {
+	driver.Row
+}
+
+func (r clickhouseRow) Err() error {
+	return TranslateClickhouse(r.Row.Err())
+}
+
+func (r clickhouseRow) Scan(dest ...any) error {
+	return TranslateClickhouse(r.Row.Scan(dest...))
+}
+
+func (r clickhouseRow) ScanStruct(dest any) error {
+	return TranslateClickhouse(r.Row.ScanStruct(dest))
+}
+
+type clickhouseRows struct {
+	driver.Rows
+}
+
+func (r clickhouseRows) Err() error {
+	return TranslateClickhouse(r.Rows.Err())
+}
+
+func (r clickhouseRows) Scan(dest ...any) error {
+	return TranslateClickhouse(r.Rows.Scan(dest...))
+}
+
+func (r clickhouseRows) ScanStruct(dest any) error {
+	return TranslateClickhouse(r.Rows.ScanStruct(dest))
+}
+
+type clickhouseBatch struct {
+	driver.Batch
+}
+
+func (b clickhouseBatch) Append(v ...any) error {
+	return TranslateClickhouse(b.Batch.Append(v...))
+}
+
+func (b clickhouseBatch) AppendStruct(v any) error {
+	return TranslateClickhouse(b.Batch.AppendStruct(v))
+}
+
+func (b clickhouseBatch) Flush() error {
+	return TranslateClickhouse(b.Batch.Flush())
+}
+
+func (b clickhouseBatch) Send() error {
+	return TranslateClickhouse(b.Batch.Send())
+}
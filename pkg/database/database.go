@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	stdErrors "errors"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+// Translate converts the errors of the Postgres and ClickHouse drivers to
+// errors.Error, see TranslatePostgres and TranslateClickhouse.
+func Translate(err error) error {
+	if untranslated(err) {
+		return err
+	}
+	if translated, ok := translatePostgres(err); ok {
+		return translated
+	}
+	if translated, ok := translateClickhouse(err); ok {
+		return translated
+	}
+	if isConnectionLoss(err) {
+		return errors.Temporarily.Wrap(err, "database connection failed")
+	}
+	return errors.Internal.Wrap(err, "database error")
+}
+
+// untranslated reports the errors returned as is: errors.Error values and the
+// cancellation of the context, which is the decision of the caller rather
+// than a failure of the database.
+func untranslated(err error) bool {
+	return err == nil || stdErrors.Is(err, context.Canceled) || errors.As(err, new(errors.Error))
+}
+
+// connectionErrnos are the errors of connections which may succeed on retry.
+var connectionErrnos = []syscall.Errno{
+	syscall.ECONNREFUSED,
+	syscall.ECONNRESET,
+	syscall.ECONNABORTED,
+	syscall.EPIPE,
+	syscall.ETIMEDOUT,
+}
+
+// isConnectionLoss reports timeouts, refused, reset and closed connections.
+// Other network errors, e.g. unknown hosts and TLS failures, are permanent.
+func isConnectionLoss(err error) bool {
+	if stdErrors.Is(err, context.DeadlineExceeded) || stdErrors.Is(err, io.EOF) || stdErrors.Is(err, io.ErrUnexpectedEOF) || stdErrors.Is(err, net.ErrClosed) {
+		return true
+	}
+	for _, errno := range connectionErrnos {
+		if stdErrors.Is(err, errno) {
+			return true
+		}
+	}
+	var netErr net.Error
+	return stdErrors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,250 @@
+package database
+
+import (
+	"context"
+	stdErrors "errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+)
+
+// postgresTypes maps SQLSTATE codes and classes, the first two characters of
+// the codes, to error types.
+var postgresTypes = map[string]errors.ErrorType{
+	"23":    errors.Logical,     // integrity_constraint_violation, e.g. unique_violation 23505 and foreign_key_violation 23503
+	"08":    errors.Temporarily, // connection_exception
+	"40001": errors.Temporarily, // serialization_failure
+	"40P01": errors.Temporarily, // deadlock_detected
+	"53300": errors.Temporarily, // too_many_connections
+	"55P03": errors.Temporarily, // lock_not_available
+	"57P01": errors.Temporarily, // admin_shutdown
+	"57P02": errors.Temporarily, // crash_shutdown
+	"57P03": errors.Temporarily, // cannot_connect_now
+}
+
+// TranslatePostgres converts pgx errors to errors.Error:
+//   - pgx.ErrNoRows to NotFound
+//   - constraint violations to Logical with the constraint, table and column
+//     in context
+//   - serialization failures, deadlocks and connection loss to Temporarily
+//   - other errors, e.g. missing tables and databases, to Internal
+//
+// The detail of the server error may contain the row values, so it is only
+// logged and never added to the context.
+//
+// errors.Error values and context.Canceled are returned as is.
+func TranslatePostgres(err error) error {
+	if untranslated(err) {
+		return err
+	}
+	if translated, ok := translatePostgres(err); ok {
+		return translated
+	}
+	if isConnectionLoss(err) {
+		return errors.Temporarily.Wrap(err, "postgres connection failed")
+	}
+	return errors.Internal.Wrap(err, "postgres error")
+}
+
+func translatePostgres(err error) (error, bool) {
+	if stdErrors.Is(err, pgx.ErrNoRows) {
+		return errors.NotFound.Wrap(err, "postgres"), true
+	}
+
+	var pgErr *pgconn.PgError
+	if stdErrors.As(err, &pgErr) {
+		errorType, ok := postgresTypes[pgErr.Code]
+		if !ok && len(pgErr.Code) >= 2 {
+			errorType, ok = postgresTypes[pgErr.Code[:2]]
+		}
+		if !ok {
+			errorType = errors.Internal
+		}
+
+		translated := errorType.Wrap(err, "postgres")
+		translated.AddToContext("sqlstate", pgErr.Code)
+		for key, value := range map[string]string{
+			"constraint": pgErr.ConstraintName,
+			"schema":     pgErr.SchemaName,
+			"table":      pgErr.TableName,
+			"column":     pgErr.ColumnName,
+		} {
+			if value != "" {
+				translated.AddToContext(key, value)
+			}
+		}
+		if pgErr.Detail != "" {
+			logger.Debug().Str("sqlstate", pgErr.Code).Str("detail", pgErr.Detail).Msg("postgres error")
+		}
+		return translated, true
+	}
+
+	if pgconn.Timeout(err) || pgconn.SafeToRetry(err) {
+		return errors.Temporarily.Wrap(err, "postgres connection failed"), true
+	}
+
+	return nil, false
+}
+
+// PostgresPool translates the errors of the queries, batches, copies and
+// transactions run on the pool with TranslatePostgres. Only the errors of
+// Acquire and AcquireFunc are translated for the connections they give, the
+// calls on *pgxpool.Conn, Tx.Conn and Tx.LargeObjects return the pgx errors.
+type PostgresPool struct {
+	*pgxpool.Pool
+}
+
+func NewPostgresPool(pool *pgxpool.Pool) *PostgresPool {
+	return &PostgresPool{Pool: pool}
+}
+
+func (p *PostgresPool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tag, err := p.Pool.Exec(ctx, sql, args...)
+	return tag, TranslatePostgres(err)
+}
+
+func (p *PostgresPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := p.Pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, TranslatePostgres(err)
+	}
+	return postgresRows{Rows: rows}, nil
+}
+
+func (p *PostgresPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return postgresRow{row: p.Pool.QueryRow(ctx, sql, args...)}
+}
+
+func (p *PostgresPool) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return postgresBatchResults{BatchResults: p.Pool.SendBatch(ctx, b)}
+}
+
+func (p *PostgresPool) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	n, err := p.Pool.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	return n, TranslatePostgres(err)
+}
+
+func (p *PostgresPool) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	conn, err := p.Pool.Acquire(ctx)
+	return conn, TranslatePostgres(err)
+}
+
+func (p *PostgresPool) AcquireFunc(ctx context.Context, f func(*pgxpool.Conn) error) error {
+	return TranslatePostgres(p.Pool.AcquireFunc(ctx, f))
+}
+
+func (p *PostgresPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return p.BeginTx(ctx, pgx.TxOptions{})
+}
+
+func (p *PostgresPool) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	tx, err := p.Pool.BeginTx(ctx, txOptions)
+	if err != nil {
+		return nil, TranslatePostgres(err)
+	}
+	return postgresTx{Tx: tx}, nil
+}
+
+func (p *PostgresPool) Ping(ctx context.Context) error {
+	return TranslatePostgres(p.Pool.Ping(ctx))
+}
+
+type postgresRow struct {
+	row pgx.Row
+}
+
+func (r postgresRow) Scan(dest ...any) error {
+	return TranslatePostgres(r.row.Scan(dest...))
+}
+
+type postgresRows struct {
+	pgx.Rows
+}
+
+func (r postgresRows) Err() error {
+	return TranslatePostgres(r.Rows.Err())
+}
+
+func (r postgresRows) Scan(dest ...any) error {
+	return TranslatePostgres(r.Rows.Scan(dest...))
+}
+
+type postgresBatchResults struct {
+	pgx.BatchResults
+}
+
+func (r postgresBatchResults) Exec() (pgconn.CommandTag, error) {
+	tag, err := r.BatchResults.Exec()
+	return tag, TranslatePostgres(err)
+}
+
+func (r postgresBatchResults) Query() (pgx.Rows, error) {
+	rows, err := r.BatchResults.Query()
+	if err != nil {
+		return nil, TranslatePostgres(err)
+	}
+	return postgresRows{Rows: rows}, nil
+}
+
+func (r postgresBatchResults) QueryRow() pgx.Row {
+	return postgresRow{row: r.BatchResults.QueryRow()}
+}
+
+func (r postgresBatchResults) Close() error {
+	return TranslatePostgres(r.BatchResults.Close())
+}
+
+type postgresTx struct {
+	pgx.Tx
+}
+
+// Begin starts a savepoint, which translates the errors as well.
+func (t postgresTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := t.Tx.Begin(ctx)
+	if err != nil {
+		return nil, TranslatePostgres(err)
+	}
+	return postgresTx{Tx: tx}, nil
+}
+
+func (t postgresTx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	n, err := t.Tx.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	return n, TranslatePostgres(err)
+}
+
+func (t postgresTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return postgresBatchResults{BatchResults: t.Tx.SendBatch(ctx, b)}
+}
+
+func (t postgresTx) Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error) {
+	description, err := t.Tx.Prepare(ctx, name, sql)
+	return description, TranslatePostgres(err)
+}
+
+func (t postgresTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tag, err := t.Tx.Exec(ctx, sql, args...)
+	return tag, TranslatePostgres(err)
+}
+
+func (t postgresTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := t.Tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, TranslatePostgres(err)
+	}
+	return postgresRows{Rows: rows}, nil
+}
+
+func (t postgresTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return postgresRow{row: t.Tx.QueryRow(ctx, sql, args...)}
+}
+
+func (t postgresTx) Commit(ctx context.Context) error {
+	return TranslatePostgres(t.Tx.Commit(ctx))
+}
+
+func (t postgresTx) Rollback(ctx context.Context) error {
+	return TranslatePostgres(t.Tx.Rollback(ctx))
+}
@@ -5,30 +5,100 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/textproto"
 	"reflect"
 	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/echo/codec"
 )
 
-type ModelBinder struct{}
+const (
+	tagQuery   = "query"
+	tagParam   = "param"
+	tagHeader  = "header"
+	tagCookie  = "cookie"
+	tagForm    = "form"
+	tagDefault = "default"
+)
+
+type ModelBinder struct {
+	// StrictJSON rejects unknown fields, duplicate keys and type mismatches of
+	// JSON bodies with a BindError. It can be enabled per route with the
+	// StrictJSON middleware.
+	StrictJSON bool
+
+	// Codecs decode the bodies of the content types other than JSON, XML and
+	// forms, codec.Respond encodes the responses with them too.
+	// Optional. Default value codec.Default.
+	Codecs *codec.Registry
+
+	// MultipartMemory is the number of bytes of multipart files kept in memory
+	// when binding *multipart.FileHeader fields, larger files go to temp files.
+	// Optional. Default value 0, every file goes to a temp file.
+	MultipartMemory int64
+
+	// FileSink stores the files bound to *File fields.
+	// Optional. Default value TempFileSink.
+	FileSink FileSink
+
+	converters map[reflect.Type]Converter
+}
 
+// Bind binds every source of the request into the model. Later sources
+// override earlier ones: default tags, query string, headers, cookies, body
+// and path params. The query string is bound by field name for GET, DELETE
+// and HEAD requests and by the query tag only for the other methods. Headers,
+// cookies and path params are bound by their tags only.
 func (b *ModelBinder) Bind(i interface{}, c echo.Context) (err error) {
+	req := c.Request()
+	bindTags := isStructPointer(i)
+
+	if bindTags {
+		if err = b.bindDefaults(i); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err = b.bindData(i, c.QueryParams(), tagQuery, !isQueryMethod(req.Method)); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err = b.bindData(i, req.Header, tagHeader, true); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err = b.bindData(i, cookieValues(req.Cookies()), tagCookie, true); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	if err = b.bindBody(i, c); err != nil {
+		return err
+	}
+
+	if bindTags {
+		if err = b.bindData(i, paramValues(c), tagParam, true); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (b *ModelBinder) bindBody(i interface{}, c echo.Context) (err error) {
 	req := c.Request()
 	if req.ContentLength == 0 {
-		if req.Method == echo.GET || req.Method == echo.DELETE || req.Method == echo.HEAD {
-			if err = b.bindData(i, c.QueryParams(), "query"); err != nil {
-				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-			}
-			return
+		if isQueryMethod(req.Method) || !hasBodyFields(reflect.TypeOf(i)) {
+			return nil
 		}
 		return echo.NewHTTPError(http.StatusBadRequest, "Request body can't be empty")
 	}
 	ctype := req.Header.Get(echo.HeaderContentType)
 	switch {
 	case strings.HasPrefix(ctype, echo.MIMEApplicationJSON):
+		if b.isStrictJSON(c) {
+			return decodeStrictJSON(req.Body, i)
+		}
 		if err = json.NewDecoder(req.Body).Decode(i); err != nil {
 			if ute, ok := err.(*json.UnmarshalTypeError); ok {
 				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal type error: expected=%v, got=%v, offset=%v", ute.Type, ute.Value, ute.Offset))
@@ -48,21 +118,50 @@ func (b *ModelBinder) Bind(i interface{}, c echo.Context) (err error) {
 				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 			}
 		}
-	case strings.HasPrefix(ctype, echo.MIMEApplicationForm), strings.HasPrefix(ctype, echo.MIMEMultipartForm):
+	case strings.HasPrefix(ctype, echo.MIMEMultipartForm):
+		return b.bindMultipart(i, c)
+	case strings.HasPrefix(ctype, echo.MIMEApplicationForm):
 		params, err := c.FormParams()
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
-		if err = b.bindData(i, params, "form"); err != nil {
+		if err = b.bindData(i, params, tagForm, false); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 		}
 	default:
-		return echo.ErrUnsupportedMediaType
+		return b.bindCodec(i, req.Body, ctype)
 	}
 	return
 }
 
-func (b *ModelBinder) bindData(ptr interface{}, data map[string][]string, tag string) error {
+// CodecRegistry returns the codecs of the binder, see codec.RegistryOf.
+func (b *ModelBinder) CodecRegistry() *codec.Registry {
+	if b.Codecs == nil {
+		return codec.Default
+	}
+	return b.Codecs
+}
+
+func (b *ModelBinder) bindCodec(i interface{}, body io.Reader, ctype string) error {
+	cd, ok := b.CodecRegistry().Get(ctype)
+	if !ok {
+		return echo.ErrUnsupportedMediaType
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err = cd.Unmarshal(data, i); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Decode error: content-type=%v, error=%v", cd.ContentType(), err.Error()))
+	}
+
+	return nil
+}
+
+// bindData binds the values to the fields by the tag. Untagged fields are
+// matched by their names unless tagsOnly is set.
+func (b *ModelBinder) bindData(ptr interface{}, data map[string][]string, tag string, tagsOnly bool) error {
 	typ := reflect.TypeOf(ptr).Elem()
 	val := reflect.ValueOf(ptr).Elem()
 
@@ -82,55 +181,179 @@ func (b *ModelBinder) bindData(ptr interface{}, data map[string][]string, tag st
 		if inputFieldName == "" {
 			inputFieldName = typeField.Name
 			// If tag is nil, we inspect if the field is a struct.
-			if _, ok := bindUnmarshaler(structField); !ok && structFieldKind == reflect.Struct {
-				err := b.bindData(structField.Addr().Interface(), data, tag)
+			if structFieldKind == reflect.Struct && !b.isValueType(structField.Type()) {
+				err := b.bindData(structField.Addr().Interface(), data, tag, tagsOnly)
 				if err != nil {
 					return err
 				}
 				continue
 			}
+			if tagsOnly {
+				continue
+			}
+		}
+		if tag == tagHeader {
+			inputFieldName = textproto.CanonicalMIMEHeaderKey(inputFieldName)
+		}
+		if structFieldKind == reflect.Map && !b.isValueType(structField.Type()) {
+			// maps are bound from name[key]=value pairs
+			if entries := mapEntries(data, inputFieldName); len(entries) > 0 {
+				if err := b.setMapEntries(structField, entries); err != nil {
+					return err
+				}
+				continue
+			}
 		}
 		inputValue, exists := data[inputFieldName]
-		if !exists {
+		if !exists || len(inputValue) == 0 {
 			continue
 		}
 
-		// Call this first, in case we're dealing with an alias to an array type
-		if ok, err := unmarshalField(typeField.Type.Kind(), inputValue[0], structField); ok {
-			if err != nil {
-				return err
-			}
+		if err := b.setField(structField, inputValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindDefaults sets the values of the default tags. Slices take a comma
+// separated list.
+func (b *ModelBinder) bindDefaults(ptr interface{}) error {
+	typ := reflect.TypeOf(ptr).Elem()
+	val := reflect.ValueOf(ptr).Elem()
+
+	for i := 0; i < typ.NumField(); i++ {
+		typeField := typ.Field(i)
+		structField := val.Field(i)
+		if !structField.CanSet() {
 			continue
 		}
 
-		numElems := len(inputValue)
-		if structFieldKind == reflect.Slice && numElems > 0 {
-			sliceOf := structField.Type().Elem().Kind()
-			slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
-			for j := 0; j < numElems; j++ {
-				if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
+		isValueType := b.isValueType(structField.Type())
+		value, ok := typeField.Tag.Lookup(tagDefault)
+		if !ok {
+			if !isValueType && structField.Kind() == reflect.Struct {
+				if err := b.bindDefaults(structField.Addr().Interface()); err != nil {
 					return err
 				}
 			}
-			val.Field(i).Set(slice)
-		} else {
-			if err := setWithProperType(typeField.Type.Kind(), inputValue[0], structField); err != nil {
-				return err
-			}
+			continue
+		}
+
+		values := []string{value}
+		if structField.Kind() == reflect.Slice && !isValueType {
+			values = strings.Split(value, ",")
+		}
+		if err := b.setField(structField, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setField sets the field from the values. Every value is an element of a
+// slice field, other fields take the first value.
+func (b *ModelBinder) setField(structField reflect.Value, inputValue []string) error {
+	if structField.Kind() != reflect.Slice || b.isValueType(structField.Type()) {
+		return b.setWithProperType(structField.Kind(), inputValue[0], structField)
+	}
+
+	numElems := len(inputValue)
+	sliceOf := structField.Type().Elem().Kind()
+	slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
+	for j := 0; j < numElems; j++ {
+		if err := b.setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
+			return err
 		}
 	}
+	structField.Set(slice)
 	return nil
 }
 
-func setWithProperType(valueKind reflect.Kind, val string, structField reflect.Value) error {
-	// But also call it here, in case we're dealing with an array of BindUnmarshalers
+// hasBodyFields reports whether the model expects a request body, that is
+// whether any of its fields is not bound from the query string, headers,
+// cookies or path params.
+func hasBodyFields(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		if hasSourceTag(field) || field.Tag.Get("json") == "-" {
+			continue
+		}
+		if field.Anonymous {
+			if hasBodyFields(field.Type) {
+				return true
+			}
+			continue
+		}
+		return true
+	}
+
+	return false
+}
+
+func hasSourceTag(field reflect.StructField) bool {
+	for _, tag := range []string{tagQuery, tagParam, tagHeader, tagCookie} {
+		if field.Tag.Get(tag) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isQueryMethod(method string) bool {
+	return method == echo.GET || method == echo.DELETE || method == echo.HEAD
+}
+
+func isStructPointer(i interface{}) bool {
+	typ := reflect.TypeOf(i)
+	return typ != nil && typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Struct
+}
+
+func paramValues(c echo.Context) map[string][]string {
+	names := c.ParamNames()
+	values := c.ParamValues()
+	data := make(map[string][]string, len(names))
+	for i, name := range names {
+		if i < len(values) {
+			data[name] = []string{values[i]}
+		}
+	}
+	return data
+}
+
+func cookieValues(cookies []*http.Cookie) map[string][]string {
+	data := make(map[string][]string, len(cookies))
+	for _, cookie := range cookies {
+		data[cookie.Name] = append(data[cookie.Name], cookie.Value)
+	}
+	return data
+}
+
+func (b *ModelBinder) setWithProperType(valueKind reflect.Kind, val string, structField reflect.Value) error {
+	// Call this first, in case we're dealing with an alias to an array type
 	if ok, err := unmarshalField(valueKind, val, structField); ok {
 		return err
 	}
+	if ok, err := b.convertField(val, structField); ok {
+		return err
+	}
 
 	switch valueKind {
 	case reflect.Ptr:
-		return setWithProperType(structField.Elem().Kind(), val, structField.Elem())
+		if structField.IsNil() {
+			structField.Set(reflect.New(structField.Type().Elem()))
+		}
+		return b.setWithProperType(structField.Elem().Kind(), val, structField.Elem())
 	case reflect.Int:
 		return setIntField(val, 0, structField)
 	case reflect.Int8:
@@ -159,6 +382,10 @@ func setWithProperType(valueKind reflect.Kind, val string, structField reflect.V
 		return setFloatField(val, 64, structField)
 	case reflect.String:
 		structField.SetString(val)
+	case reflect.Slice:
+		return b.setSliceField(val, structField)
+	case reflect.Map:
+		return b.setMapField(val, structField)
 	default:
 		return errors.New("unknown type")
 	}
@@ -196,6 +423,9 @@ func unmarshalFieldNonPtr(value string, field reflect.Value) (bool, error) {
 }
 
 func unmarshalFieldPtr(value string, field reflect.Value) (bool, error) {
+	if _, ok := bindUnmarshaler(reflect.New(field.Type().Elem()).Elem()); !ok {
+		return false, nil
+	}
 	if field.IsNil() {
 		// Initialize the pointer to a nil value
 		field.Set(reflect.New(field.Type().Elem()))
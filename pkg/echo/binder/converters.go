@@ -0,0 +1,208 @@
+package binder
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gagliardetto/solana-go"
+	"github.com/labstack/echo/v4"
+	"github.com/shopspring/decimal"
+)
+
+// Converter parses a query, form, header, cookie or path param value. The
+// result must be a value of the converted type or a pointer to it.
+type Converter func(value string) (interface{}, error)
+
+var bindUnmarshalerType = reflect.TypeOf((*echo.BindUnmarshaler)(nil)).Elem()
+
+var defaultConverters = map[reflect.Type]Converter{
+	reflect.TypeOf(time.Time{}): func(value string) (interface{}, error) {
+		return parseTime(value)
+	},
+	reflect.TypeOf(time.Duration(0)): func(value string) (interface{}, error) {
+		return time.ParseDuration(value)
+	},
+	reflect.TypeOf(big.Int{}): func(value string) (interface{}, error) {
+		n, ok := new(big.Int).SetString(value, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", value)
+		}
+		return n, nil
+	},
+	reflect.TypeOf(decimal.Decimal{}): func(value string) (interface{}, error) {
+		return decimal.NewFromString(value)
+	},
+	reflect.TypeOf(solana.PublicKey{}): func(value string) (interface{}, error) {
+		return solana.PublicKeyFromBase58(value)
+	},
+	reflect.TypeOf(solana.Signature{}): func(value string) (interface{}, error) {
+		return solana.SignatureFromBase58(value)
+	},
+}
+
+// RegisterConverter sets the converter for the type of sample, overriding the
+// built-in conversion. Converters must be registered before the server starts.
+func (b *ModelBinder) RegisterConverter(sample interface{}, converter Converter) {
+	if b.converters == nil {
+		b.converters = make(map[reflect.Type]Converter)
+	}
+	b.converters[reflect.TypeOf(sample)] = converter
+}
+
+func (b *ModelBinder) converter(t reflect.Type) Converter {
+	if converter, ok := b.converters[t]; ok {
+		return converter
+	}
+	return defaultConverters[t]
+}
+
+// isValueType reports whether the type is bound from a single value instead
+// of being walked field by field or element by element.
+func (b *ModelBinder) isValueType(t reflect.Type) bool {
+	if b.converter(t) != nil {
+		return true
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return true
+	}
+	ptr := reflect.PtrTo(t)
+	return ptr.Implements(bindUnmarshalerType) || ptr.Implements(textUnmarshalerType)
+}
+
+// convertField sets the field with a registered converter or the
+// encoding.TextUnmarshaler of its type.
+func (b *ModelBinder) convertField(value string, field reflect.Value) (bool, error) {
+	if converter := b.converter(field.Type()); converter != nil {
+		result, err := converter(value)
+		if err != nil {
+			return true, err
+		}
+		return true, assignConverted(field, result)
+	}
+
+	if field.Kind() != reflect.Ptr && field.CanAddr() {
+		if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return true, unmarshaler.UnmarshalText([]byte(value))
+		}
+	}
+
+	return false, nil
+}
+
+func assignConverted(field reflect.Value, result interface{}) error {
+	value := reflect.ValueOf(result)
+	if value.Kind() == reflect.Ptr && !value.IsNil() && value.Type().Elem() == field.Type() {
+		value = value.Elem()
+	}
+	if !value.IsValid() || !value.Type().AssignableTo(field.Type()) {
+		return fmt.Errorf("converter returned %T for %s", result, field.Type())
+	}
+	field.Set(value)
+	return nil
+}
+
+// setSliceField sets a byte slice from hex (0x prefixed) or base64 and any
+// other slice from a comma separated list.
+func (b *ModelBinder) setSliceField(value string, field reflect.Value) error {
+	if field.Type().Elem().Kind() == reflect.Uint8 {
+		data, err := parseBytes(value)
+		if err != nil {
+			return err
+		}
+		field.SetBytes(data)
+		return nil
+	}
+
+	parts := strings.Split(value, ",")
+	slice := reflect.MakeSlice(field.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		if err := b.setWithProperType(slice.Index(i).Kind(), part, slice.Index(i)); err != nil {
+			return err
+		}
+	}
+	field.Set(slice)
+	return nil
+}
+
+// setMapField sets a map from a comma separated list of key:value pairs.
+func (b *ModelBinder) setMapField(value string, field reflect.Value) error {
+	entries := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(pair, ":")
+		if !ok {
+			return fmt.Errorf("invalid map entry %q", pair)
+		}
+		entries[key] = val
+	}
+	return b.setMapEntries(field, entries)
+}
+
+func (b *ModelBinder) setMapEntries(field reflect.Value, entries map[string]string) error {
+	typ := field.Type()
+	if field.IsNil() {
+		field.Set(reflect.MakeMapWithSize(typ, len(entries)))
+	}
+
+	for k, v := range entries {
+		key := reflect.New(typ.Key()).Elem()
+		if err := b.setWithProperType(key.Kind(), k, key); err != nil {
+			return err
+		}
+		elem := reflect.New(typ.Elem()).Elem()
+		if err := b.setWithProperType(elem.Kind(), v, elem); err != nil {
+			return err
+		}
+		field.SetMapIndex(key, elem)
+	}
+	return nil
+}
+
+// mapEntries collects the name[key]=value pairs of the data.
+func mapEntries(data map[string][]string, name string) map[string]string {
+	var entries map[string]string
+	prefix := name + "["
+	for k, v := range data {
+		if len(v) == 0 || !strings.HasPrefix(k, prefix) || !strings.HasSuffix(k, "]") {
+			continue
+		}
+		if entries == nil {
+			entries = make(map[string]string)
+		}
+		entries[k[len(prefix):len(k)-1]] = v[0]
+	}
+	return entries
+}
+
+// parseTime accepts RFC 3339 timestamps, dates and unix seconds.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+func parseBytes(value string) ([]byte, error) {
+	if len(value) >= 2 && value[0] == '0' && (value[1] == 'x' || value[1] == 'X') {
+		return hex.DecodeString(value[2:])
+	}
+	if data, err := base64.StdEncoding.DecodeString(value); err == nil {
+		return data, nil
+	}
+	if data, err := base64.URLEncoding.DecodeString(value); err == nil {
+		return data, nil
+	}
+	return base64.RawURLEncoding.DecodeString(value)
+}
@@ -0,0 +1,325 @@
+package binder
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/bytes"
+)
+
+const uploadsContextKey = "binder.uploads"
+
+// maxValueBytes limits the non-file parts of streamed multipart requests like
+// net/http does for parsed ones.
+const maxValueBytes = 10 << 20
+
+var (
+	fileHeaderType = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileType       = reflect.TypeOf((*File)(nil))
+)
+
+// File is an uploaded file bound to *File and []*File fields. Its content is
+// streamed to the FileSink of the binder while the request is read.
+type File struct {
+	Field    string
+	Filename string
+	// ContentType is detected from the content, DeclaredContentType is sent
+	// by the client.
+	ContentType         string
+	DeclaredContentType string
+	Size                int64
+	// Path is the location of the content set by the sink.
+	Path string
+}
+
+// Open opens the content of a file stored on the local disk.
+func (f *File) Open() (io.ReadCloser, error) {
+	return os.Open(f.Path)
+}
+
+// FileSink stores the content of uploaded files.
+type FileSink interface {
+	// Store consumes the content and sets the location of the file.
+	Store(ctx context.Context, file *File, content io.Reader) error
+}
+
+// FileRemover is implemented by sinks whose files are temporary. The files are
+// removed by the MultipartCleanup middleware at the end of the request.
+type FileRemover interface {
+	Remove(file *File) error
+}
+
+// TempFileSink stores the files in the temp directory.
+type TempFileSink struct {
+	// Dir is the directory of the files.
+	// Optional. Default value os.TempDir().
+	Dir string
+}
+
+func (s TempFileSink) Store(_ context.Context, file *File, content io.Reader) error {
+	f, err := os.CreateTemp(s.Dir, "upload-*")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	file.Path = f.Name()
+	_, err = io.Copy(f, content)
+	return err
+}
+
+func (s TempFileSink) Remove(file *File) error {
+	if file.Path == "" {
+		return nil
+	}
+	return os.Remove(file.Path)
+}
+
+type uploads struct {
+	form  *multipart.Form
+	sink  FileSink
+	files []*File
+}
+
+// MultipartCleanup removes the uploaded files of the request when the handler
+// returns.
+func MultipartCleanup() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			defer func() {
+				u, ok := c.Get(uploadsContextKey).(*uploads)
+				if !ok {
+					return
+				}
+				if u.form != nil {
+					_ = u.form.RemoveAll()
+				}
+				if remover, ok := u.sink.(FileRemover); ok {
+					for _, file := range u.files {
+						_ = remover.Remove(file)
+					}
+				}
+			}()
+			return next(c)
+		}
+	}
+}
+
+func requestUploads(c echo.Context) *uploads {
+	u, ok := c.Get(uploadsContextKey).(*uploads)
+	if !ok {
+		u = &uploads{}
+		c.Set(uploadsContextKey, u)
+	}
+	return u
+}
+
+// bindMultipart streams the files to the sink when the model has *File
+// fields and parses the form into memory and temp files otherwise.
+func (b *ModelBinder) bindMultipart(i interface{}, c echo.Context) error {
+	if !isStructPointer(i) {
+		return echo.NewHTTPError(http.StatusBadRequest, "binding element must be a struct")
+	}
+
+	fields := fileFields(reflect.TypeOf(i).Elem(), fileType)
+	if len(fields) > 0 {
+		return b.streamMultipart(i, c, fields)
+	}
+
+	req := c.Request()
+	if err := req.ParseMultipartForm(b.MultipartMemory); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	requestUploads(c).form = req.MultipartForm
+
+	if err := b.bindData(i, req.Form, tagForm, false); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	files := make(map[string][]reflect.Value, len(req.MultipartForm.File))
+	for name, headers := range req.MultipartForm.File {
+		for _, header := range headers {
+			files[name] = append(files[name], reflect.ValueOf(header))
+		}
+	}
+	bindFiles(reflect.ValueOf(i).Elem(), files, fileHeaderType)
+
+	return nil
+}
+
+func (b *ModelBinder) streamMultipart(i interface{}, c echo.Context, fields map[string]int64) error {
+	req := c.Request()
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	sink := b.FileSink
+	if sink == nil {
+		sink = TempFileSink{}
+	}
+	u := requestUploads(c)
+	u.sink = sink
+
+	values := make(map[string][]string)
+	files := make(map[string][]reflect.Value)
+	valueBytes := int64(0)
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+
+		if part.FileName() == "" {
+			data, err := io.ReadAll(io.LimitReader(part, maxValueBytes-valueBytes+1))
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			if valueBytes += int64(len(data)); valueBytes > maxValueBytes {
+				return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "multipart form values are too large")
+			}
+			values[name] = append(values[name], string(data))
+			continue
+		}
+
+		limit, ok := fields[name]
+		if !ok {
+			// the model has no field for the file
+			continue
+		}
+
+		content := bufio.NewReaderSize(part, 512)
+		head, _ := content.Peek(512)
+		file := &File{
+			Field:               name,
+			Filename:            part.FileName(),
+			ContentType:         http.DetectContentType(head),
+			DeclaredContentType: part.Header.Get(echo.HeaderContentType),
+		}
+		u.files = append(u.files, file)
+
+		counter := &limitedReader{reader: content, limit: limit}
+		if err = sink.Store(req.Context(), file, counter); err != nil {
+			if counter.exceeded {
+				return echo.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("file '%s' is larger than %d bytes", name, limit))
+			}
+			return err
+		}
+		file.Size = counter.read
+		files[name] = append(files[name], reflect.ValueOf(file))
+	}
+
+	if err = b.bindData(i, values, tagForm, false); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	bindFiles(reflect.ValueOf(i).Elem(), files, fileType)
+
+	return nil
+}
+
+// bindFiles sets the fields of type t and []t from the files by form name.
+func bindFiles(val reflect.Value, files map[string][]reflect.Value, t reflect.Type) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		typeField := typ.Field(i)
+		structField := val.Field(i)
+		if !structField.CanSet() {
+			continue
+		}
+
+		if typeField.Type.Kind() == reflect.Struct {
+			bindFiles(structField, files, t)
+			continue
+		}
+
+		values := files[formName(typeField)]
+		if len(values) == 0 {
+			continue
+		}
+
+		switch typeField.Type {
+		case t:
+			structField.Set(values[0])
+		case reflect.SliceOf(t):
+			slice := reflect.MakeSlice(typeField.Type, 0, len(values))
+			structField.Set(reflect.Append(slice, values...))
+		}
+	}
+}
+
+// fileFields returns the form names of the fields of type t and []t with the
+// max_size of their validate tags, zero meaning no limit.
+func fileFields(typ reflect.Type, t reflect.Type) map[string]int64 {
+	fields := make(map[string]int64)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			for name, limit := range fileFields(field.Type, t) {
+				fields[name] = limit
+			}
+			continue
+		}
+		if field.Type == t || field.Type == reflect.SliceOf(t) {
+			fields[formName(field)] = maxSizeRule(field.Tag.Get("validate"))
+		}
+	}
+	return fields
+}
+
+func formName(field reflect.StructField) string {
+	if name := field.Tag.Get(tagForm); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// maxSizeRule parses the max_size=10MB validation rule so that oversized
+// uploads are rejected while streaming.
+func maxSizeRule(tag string) int64 {
+	for _, rule := range strings.Split(tag, ",") {
+		if strings.HasPrefix(rule, "max_size=") {
+			size, err := bytes.Parse(strings.TrimPrefix(rule, "max_size="))
+			if err == nil {
+				return size
+			}
+		}
+	}
+	return 0
+}
+
+type limitedReader struct {
+	reader   io.Reader
+	limit    int64
+	read     int64
+	exceeded bool
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if r.limit > 0 && r.read > r.limit {
+		r.exceeded = true
+		return n, fmt.Errorf("file is larger than %d bytes", r.limit)
+	}
+	return n, err
+}
@@ -0,0 +1,455 @@
+package binder
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// StrictJSONContextKey enables strict JSON decoding for a single request.
+const StrictJSONContextKey = "binder.strict_json"
+
+const (
+	TagUnknownField = "unknown_field"
+	TagDuplicateKey = "duplicate_key"
+	TagType         = "type"
+	TagSyntax       = "syntax"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// StrictJSON enables strict JSON decoding for the routes it is applied to.
+func StrictJSON() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(StrictJSONContextKey, true)
+			return next(c)
+		}
+	}
+}
+
+// FieldError describes a problem of the request body at the JSON pointer Path.
+type FieldError struct {
+	Path     string
+	Field    string
+	Tag      string
+	TagParam string
+	Message  string
+}
+
+// BindError is returned by the strict JSON decoding and lists every problem
+// found in the body.
+type BindError struct {
+	Message string
+	Fields  []FieldError
+}
+
+func (e *BindError) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Message
+	}
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, field.Message)
+	}
+	return e.Message + ": " + strings.Join(messages, "; ")
+}
+
+func (b *ModelBinder) isStrictJSON(c echo.Context) bool {
+	if b.StrictJSON {
+		return true
+	}
+	strict, _ := c.Get(StrictJSONContextKey).(bool)
+	return strict
+}
+
+// decodeStrictJSON checks the body against the model before decoding it:
+// unknown fields, duplicate keys and type mismatches are all reported.
+func decodeStrictJSON(body io.Reader, i interface{}) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return &BindError{Message: "Request body can't be read"}
+	}
+
+	w := &jsonWalker{dec: json.NewDecoder(bytes.NewReader(data))}
+	w.dec.UseNumber()
+
+	if err = w.value(reflect.TypeOf(i), "", false); err == nil && w.dec.More() {
+		err = fmt.Errorf("unexpected data after the JSON value")
+	}
+	if err != nil {
+		w.add("", TagSyntax, "", fmt.Sprintf("Syntax error: offset=%v, error=%v", w.dec.InputOffset(), err))
+	}
+	if len(w.errors) > 0 {
+		return &BindError{Message: "Request body is invalid", Fields: w.errors}
+	}
+
+	if err = json.Unmarshal(data, i); err != nil {
+		return &BindError{Message: "Request body is invalid", Fields: []FieldError{{
+			Tag:     TagType,
+			Message: err.Error(),
+		}}}
+	}
+
+	return nil
+}
+
+type jsonWalker struct {
+	dec    *json.Decoder
+	errors []FieldError
+}
+
+func (w *jsonWalker) add(path string, tag string, param string, message string) {
+	w.errors = append(w.errors, FieldError{
+		Path:     path,
+		Field:    lastPointerSegment(path),
+		Tag:      tag,
+		TagParam: param,
+		Message:  message,
+	})
+}
+
+// value consumes the next JSON value and checks it against t. Only syntax
+// errors are returned, everything else is collected.
+func (w *jsonWalker) value(t reflect.Type, path string, quoted bool) error {
+	for t != nil && t.Kind() == reflect.Ptr {
+		if t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType) {
+			break
+		}
+		t = t.Elem()
+	}
+
+	token, err := w.dec.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+
+	if t == nil || t.Kind() == reflect.Interface || hasCustomUnmarshaler(t) {
+		return w.skip(token)
+	}
+
+	got := jsonKind(token)
+	mismatch := func(expected string) error {
+		w.add(path, TagType, expected, fmt.Sprintf("Invalid field '%s': expected %s, got %s", pathOrRoot(path), expected, got))
+		return w.skip(token)
+	}
+
+	if quoted {
+		if s, ok := token.(string); ok && isScalar(t.Kind()) {
+			token = json.Number(s)
+			if t.Kind() == reflect.String {
+				token = s
+			} else if t.Kind() == reflect.Bool {
+				if v, err := strconv.ParseBool(s); err == nil {
+					token = v
+				}
+			}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if token != json.Delim('{') {
+			return mismatch("object")
+		}
+		return w.object(t, path)
+	case reflect.Map:
+		if token != json.Delim('{') {
+			return mismatch("object")
+		}
+		return w.mapObject(t, path)
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			if _, ok := token.(string); !ok {
+				return mismatch("string")
+			}
+			return nil
+		}
+		if token != json.Delim('[') {
+			return mismatch("array")
+		}
+		return w.array(t, path)
+	case reflect.String:
+		if _, ok := token.(string); !ok {
+			return mismatch("string")
+		}
+	case reflect.Bool:
+		if _, ok := token.(bool); !ok {
+			return mismatch("boolean")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := token.(json.Number)
+		if !ok {
+			return mismatch("integer")
+		}
+		if _, err := strconv.ParseInt(string(n), 10, t.Bits()); err != nil {
+			w.add(path, TagType, t.Kind().String(), fmt.Sprintf("Invalid field '%s': %s is not a valid %s", pathOrRoot(path), n, t.Kind()))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := token.(json.Number)
+		if !ok {
+			return mismatch("integer")
+		}
+		if _, err := strconv.ParseUint(string(n), 10, t.Bits()); err != nil {
+			w.add(path, TagType, t.Kind().String(), fmt.Sprintf("Invalid field '%s': %s is not a valid %s", pathOrRoot(path), n, t.Kind()))
+		}
+	case reflect.Float32, reflect.Float64:
+		n, ok := token.(json.Number)
+		if !ok {
+			return mismatch("number")
+		}
+		if _, err := strconv.ParseFloat(string(n), t.Bits()); err != nil {
+			w.add(path, TagType, t.Kind().String(), fmt.Sprintf("Invalid field '%s': %s is not a valid %s", pathOrRoot(path), n, t.Kind()))
+		}
+	default:
+		return mismatch(t.Kind().String())
+	}
+
+	return nil
+}
+
+func (w *jsonWalker) object(t reflect.Type, path string) error {
+	fields := jsonFields(t)
+	seen := make(map[int]string)
+
+	for w.dec.More() {
+		token, err := w.dec.Token()
+		if err != nil {
+			return err
+		}
+		key := token.(string)
+		keyPath := path + "/" + escapePointer(key)
+
+		idx, ok := fields.lookup(key)
+		if !ok {
+			w.add(keyPath, TagUnknownField, "", fmt.Sprintf("Unknown field '%s'", keyPath))
+			if err = w.skipValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		if previous, ok := seen[idx]; ok {
+			w.add(keyPath, TagDuplicateKey, previous, fmt.Sprintf("Duplicate key '%s'", keyPath))
+		}
+		seen[idx] = key
+
+		field := fields.list[idx]
+		if err = w.value(field.typ, keyPath, field.quoted); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.dec.Token()
+	return err
+}
+
+func (w *jsonWalker) mapObject(t reflect.Type, path string) error {
+	seen := make(map[string]bool)
+
+	for w.dec.More() {
+		token, err := w.dec.Token()
+		if err != nil {
+			return err
+		}
+		key := token.(string)
+		keyPath := path + "/" + escapePointer(key)
+
+		if seen[key] {
+			w.add(keyPath, TagDuplicateKey, key, fmt.Sprintf("Duplicate key '%s'", keyPath))
+		}
+		seen[key] = true
+
+		if err = w.value(t.Elem(), keyPath, false); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.dec.Token()
+	return err
+}
+
+func (w *jsonWalker) array(t reflect.Type, path string) error {
+	for i := 0; w.dec.More(); i++ {
+		if t.Kind() == reflect.Array && i >= t.Len() {
+			w.add(path, TagType, strconv.Itoa(t.Len()), fmt.Sprintf("Invalid field '%s': expected at most %d elements", pathOrRoot(path), t.Len()))
+			if err := w.skipValue(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.value(t.Elem(), path+"/"+strconv.Itoa(i), false); err != nil {
+			return err
+		}
+	}
+
+	_, err := w.dec.Token()
+	return err
+}
+
+func (w *jsonWalker) skipValue() error {
+	token, err := w.dec.Token()
+	if err != nil {
+		return err
+	}
+	return w.skip(token)
+}
+
+// skip consumes the rest of the value started by token.
+func (w *jsonWalker) skip(token json.Token) error {
+	if token != json.Delim('{') && token != json.Delim('[') {
+		return nil
+	}
+	for depth := 1; depth > 0; {
+		token, err := w.dec.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+type jsonField struct {
+	name   string
+	typ    reflect.Type
+	quoted bool
+}
+
+type jsonFieldSet struct {
+	list   []jsonField
+	byName map[string]int
+}
+
+// lookup matches the key like encoding/json does: exactly first, then case
+// insensitively.
+func (s *jsonFieldSet) lookup(key string) (int, bool) {
+	if idx, ok := s.byName[key]; ok {
+		return idx, true
+	}
+	for idx, field := range s.list {
+		if strings.EqualFold(field.name, key) {
+			return idx, true
+		}
+	}
+	return 0, false
+}
+
+// jsonFields lists the fields decoded from the body. Fields bound from other
+// sources without an explicit json tag are not part of the body.
+func jsonFields(t reflect.Type) *jsonFieldSet {
+	set := &jsonFieldSet{byName: make(map[string]int)}
+	var embedded []reflect.Type
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, hasTag := field.Tag.Lookup("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				embedded = append(embedded, fieldType)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if !hasTag && hasSourceTag(field) {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		set.byName[name] = len(set.list)
+		set.list = append(set.list, jsonField{
+			name:   name,
+			typ:    field.Type,
+			quoted: strings.Contains(","+opts+",", ",string,"),
+		})
+	}
+
+	// promoted fields never shadow the fields of the outer struct
+	for _, fieldType := range embedded {
+		for _, field := range jsonFields(fieldType).list {
+			if _, ok := set.byName[field.name]; ok {
+				continue
+			}
+			set.byName[field.name] = len(set.list)
+			set.list = append(set.list, field)
+		}
+	}
+
+	return set
+}
+
+func hasCustomUnmarshaler(t reflect.Type) bool {
+	return t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType) ||
+		reflect.PtrTo(t).Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+func isScalar(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array, reflect.Interface:
+		return false
+	}
+	return true
+}
+
+func jsonKind(token json.Token) string {
+	switch token.(type) {
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case json.Delim:
+		if token == json.Delim('[') {
+			return "array"
+		}
+		return "object"
+	}
+	return "null"
+}
+
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+func lastPointerSegment(path string) string {
+	segment := path[strings.LastIndex(path, "/")+1:]
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,159 @@
+package codec
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	MIMEApplicationMsgPack  = "application/msgpack"
+	MIMEApplicationCBOR     = "application/cbor"
+	MIMEApplicationProtobuf = "application/x-protobuf"
+)
+
+// Codec encodes and decodes request and response bodies of a MIME type.
+type Codec interface {
+	// ContentType is the MIME type the codec responds with.
+	ContentType() string
+	// Aliases are other MIME types the codec accepts.
+	Aliases() []string
+	// Supports reports whether the value can be encoded, e.g. the protobuf
+	// codec supports proto.Message values only.
+	Supports(v interface{}) bool
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// Registry holds the codecs by MIME type. JSON is always the fallback of the
+// content negotiation.
+type Registry struct {
+	mu     sync.RWMutex
+	codecs map[string]Codec
+}
+
+// Default is used by the binder and by Respond unless the binder has its own.
+var Default = NewRegistry(JSON{}, MsgPack{}, CBOR{}, Protobuf{})
+
+func NewRegistry(codecs ...Codec) *Registry {
+	r := &Registry{codecs: make(map[string]Codec)}
+	for _, c := range codecs {
+		r.Register(c)
+	}
+	return r
+}
+
+// Register adds the codec, replacing the codecs of the same MIME types.
+func (r *Registry) Register(c Codec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.codecs[c.ContentType()] = c
+	for _, alias := range c.Aliases() {
+		r.codecs[alias] = c
+	}
+}
+
+// Get returns the codec of a Content-Type header value.
+func (r *Registry) Get(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.codecs[mediaType]
+	return c, ok
+}
+
+// Negotiate picks the codec for the Accept header value which supports v.
+func (r *Registry) Negotiate(accept string, v interface{}) Codec {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, mediaType := range parseAccept(accept) {
+		if c, ok := r.codecs[mediaType]; ok && c.Supports(v) {
+			return c
+		}
+	}
+
+	if c, ok := r.codecs[echo.MIMEApplicationJSON]; ok {
+		return c
+	}
+	return JSON{}
+}
+
+// Respond writes v in the format negotiated by the Accept header of the
+// request. JSON responses go through the echo serializer.
+func (r *Registry) Respond(c echo.Context, status int, v interface{}) error {
+	cd := r.Negotiate(c.Request().Header.Get(echo.HeaderAccept), v)
+	if _, ok := cd.(JSON); ok {
+		return c.JSON(status, v)
+	}
+
+	data, err := cd.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Blob(status, cd.ContentType(), data)
+}
+
+// RegistryOf returns the codecs of the binder of the server, so that requests
+// and responses use the same codecs, or Default when the binder has none.
+func RegistryOf(c echo.Context) *Registry {
+	if e := c.Echo(); e != nil {
+		if b, ok := e.Binder.(interface{ CodecRegistry() *Registry }); ok {
+			if r := b.CodecRegistry(); r != nil {
+				return r
+			}
+		}
+	}
+	return Default
+}
+
+// Respond writes v with the registry of the server, see RegistryOf.
+func Respond(c echo.Context, status int, v interface{}) error {
+	return RegistryOf(c).Respond(c, status, v)
+}
+
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept returns the media types of the header ordered by quality.
+// Wildcards are dropped since JSON is the fallback anyway.
+func parseAccept(accept string) []string {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || strings.Contains(mediaType, "*") {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	result := make([]string, len(ranges))
+	for i, r := range ranges {
+		result[i] = r.mediaType
+	}
+	return result
+}
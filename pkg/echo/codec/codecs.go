@@ -0,0 +1,95 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/labstack/echo/v4"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+type JSON struct{}
+
+func (JSON) ContentType() string         { return echo.MIMEApplicationJSON }
+func (JSON) Aliases() []string           { return nil }
+func (JSON) Supports(v interface{}) bool { return true }
+func (JSON) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+func (JSON) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// MsgPack uses the json tags of the models so that one set of tags describes
+// every format.
+type MsgPack struct{}
+
+func (MsgPack) ContentType() string { return MIMEApplicationMsgPack }
+func (MsgPack) Aliases() []string {
+	return []string{"application/x-msgpack", "application/vnd.msgpack"}
+}
+func (MsgPack) Supports(v interface{}) bool { return true }
+
+func (MsgPack) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	enc.SetOmitEmpty(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgPack) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// CBOR falls back to the json tags of the models when there are no cbor tags.
+type CBOR struct{}
+
+func (CBOR) ContentType() string         { return MIMEApplicationCBOR }
+func (CBOR) Aliases() []string           { return nil }
+func (CBOR) Supports(v interface{}) bool { return true }
+
+func (CBOR) Marshal(v interface{}) ([]byte, error) {
+	return cbor.Marshal(v)
+}
+
+func (CBOR) Unmarshal(data []byte, v interface{}) error {
+	return cbor.Unmarshal(data, v)
+}
+
+// Protobuf handles proto.Message models only.
+type Protobuf struct{}
+
+func (Protobuf) ContentType() string { return MIMEApplicationProtobuf }
+func (Protobuf) Aliases() []string {
+	return []string{"application/protobuf", "application/vnd.google.protobuf"}
+}
+
+func (Protobuf) Supports(v interface{}) bool {
+	_, ok := v.(proto.Message)
+	return ok
+}
+
+func (Protobuf) Marshal(v interface{}) ([]byte, error) {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return nil, errors.Internal.Newf("%T is not a protobuf message", v)
+	}
+	return proto.Marshal(message)
+}
+
+func (Protobuf) Unmarshal(data []byte, v interface{}) error {
+	message, ok := v.(proto.Message)
+	if !ok {
+		return errors.Validation.Newf("%T is not a protobuf message", v)
+	}
+	return proto.Unmarshal(data, message)
+}
@@ -1,33 +1,55 @@
 package errors
 
-type ErrorContext map[string]string
+import "encoding/json"
 
-func (c *ErrorContext) init() {
-	*c = make(ErrorContext)
-}
+// ErrorContext holds the details of an error. The values must be JSON-able,
+// e.g. strings, numbers, bools, time.Time, slices, maps and structs.
+type ErrorContext map[string]interface{}
 
-func (c ErrorContext) Set(key string, value string) {
-	if c == nil {
-		c.init()
+func (c *ErrorContext) init() {
+	if *c == nil {
+		*c = make(ErrorContext)
 	}
-	c[key] = value
 }
 
-func (c ErrorContext) Get(key string) (value string) {
-	if c == nil {
-		c.init()
-	}
-	if value, ok := c[key]; ok {
-		return value
-	}
+func (c *ErrorContext) Set(key string, value interface{}) {
+	c.init()
+	(*c)[key] = value
+}
 
-	return ""
+func (c ErrorContext) Get(key string) interface{} {
+	return c[key]
 }
 
 func (c ErrorContext) Len() int {
 	return len(c)
 }
 
-func (c ErrorContext) Clear() {
-	c.init()
+func (c *ErrorContext) Clear() {
+	*c = nil
+}
+
+func (c ErrorContext) clone() ErrorContext {
+	if c == nil {
+		return nil
+	}
+	clone := make(ErrorContext, len(c))
+	for k, v := range c {
+		clone[k] = v
+	}
+	return clone
+}
+
+// normalize converts the values to the types of decoded JSON, e.g. for
+// protobuf structs.
+func (c ErrorContext) normalize() (map[string]interface{}, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]interface{}
+	if err = json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
 }
@@ -1,7 +1,9 @@
 package errors
 
 import (
-	"fmt"
+	stdErrors "errors"
+	"reflect"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -31,23 +33,34 @@ func (e Error) Error() string {
 	return e.originalError.Error()
 }
 
-func (e *Error) GetType() ErrorType {
+func (e Error) GetType() ErrorType {
 	return e.errorType
 }
 
-func (e *Error) GetCode() ErrorCode {
+func (e Error) GetCode() ErrorCode {
 	return e.code
 }
 
-func (e *Error) AddToContext(key string, value string) {
+// AddToContext sets the context value of the error in place. The context may
+// be shared with the errors it was wrapped from, use With for shared errors
+// such as sentinels.
+func (e *Error) AddToContext(key string, value interface{}) {
 	e.context.Set(key, value)
 }
 
-func (e *Error) GetContextValueByKey(key string) {
-	e.context.Get(key)
+// With returns a copy of the error with the context value set, e.g.
+// ErrNotFound.With("slot", 123).
+func (e Error) With(key string, value interface{}) Error {
+	e.context = e.context.clone()
+	e.context.Set(key, value)
+	return e
+}
+
+func (e Error) GetContextValueByKey(key string) interface{} {
+	return e.context.Get(key)
 }
 
-func (e *Error) GetContext() ErrorContext {
+func (e Error) GetContext() ErrorContext {
 	return e.context
 }
 
@@ -56,23 +69,47 @@ func (e *Error) ClearContext() {
 }
 
 func (e Error) Cause() error {
-	return errors.Cause(e)
+	return errors.Cause(e.originalError)
+}
+
+// Unwrap returns the wrapped error, so the standard errors.Is and errors.As
+// walk through the error.
+func (e Error) Unwrap() error {
+	return e.originalError
+}
+
+// Is matches the target by code and type when the target has a code, e.g.
+// errors.Is(err, api.ErrValidation). A target without a code matches only the
+// error it was created as.
+func (e Error) Is(target error) bool {
+	t, ok := target.(Error)
+	if !ok {
+		return false
+	}
+	if t.code != 0 {
+		return e.code == t.code && (t.errorType == NoType || e.errorType == t.errorType)
+	}
+	// originals such as Multi hold slices, comparing them would panic
+	if e.originalError != nil && !reflect.TypeOf(e.originalError).Comparable() {
+		return false
+	}
+	return e.originalError == t.originalError
 }
 
 func New(msg string) Error {
-	return Error{errorType: NoType, originalError: errors.New(msg)}
+	return Error{errorType: NoType, originalError: newOriginal(NoType, msg)}
 }
 
 func Newf(msg string, args ...interface{}) Error {
-	return Error{errorType: NoType, originalError: errors.New(fmt.Sprintf(msg, args...))}
+	return Error{errorType: NoType, originalError: newOriginalf(NoType, msg, args...)}
 }
 
 func NewWithCode(code ErrorCode, msg string) Error {
-	return Error{code: code, errorType: NoType, originalError: errors.New(msg)}
+	return Error{code: code, errorType: NoType, originalError: newOriginal(NoType, msg)}
 }
 
 func NewfWithCode(code ErrorCode, msg string, args ...interface{}) Error {
-	return Error{code: code, errorType: NoType, originalError: errors.New(fmt.Sprintf(msg, args...))}
+	return Error{code: code, errorType: NoType, originalError: newOriginalf(NoType, msg, args...)}
 }
 
 func Wrap(err error, msg string) Error {
@@ -87,48 +124,105 @@ func Cause(err error) error {
 	return errors.Cause(err)
 }
 
+// Wrapf adds a layer keeping the type, code and context of the Error in the
+// chain of err.
 func Wrapf(err error, msg string, args ...interface{}) Error {
-	wrappedError := errors.Wrapf(err, msg, args...)
-	if customErr, ok := err.(Error); ok {
-		return Error{
-			code:          customErr.code,
-			errorType:     customErr.errorType,
-			originalError: wrappedError,
-			context:       customErr.context,
+	return wrap(NoType, 0, err, msg, args...)
+}
+
+// WrapfWithCode adds a layer with the code, keeping the type and context of
+// the Error in the chain of err.
+func WrapfWithCode(code ErrorCode, err error, msg string, args ...interface{}) Error {
+	return wrap(NoType, code, err, msg, args...)
+}
+
+// wrap builds a layer around err. Layers follow these rules:
+//   - the type and code of the outermost layer that sets them win, so the
+//     outermost type is used for the HTTP and gRPC mapping
+//   - the context is merged, keys of outer layers override inner ones
+//   - the inner layers stay in the chain and are listed by Chain
+func wrap(errorType ErrorType, code ErrorCode, err error, msg string, args ...interface{}) Error {
+	layer := Error{code: code, errorType: errorType}
+
+	var inner Error
+	if As(err, &inner) {
+		if layer.errorType == NoType {
+			layer.errorType = inner.errorType
 		}
+		if layer.code == 0 {
+			layer.code = inner.code
+		}
+		layer.context = inner.context.clone()
 	}
+	layer.originalError = wrapOriginal(layer.errorType, err, msg, args...)
 
-	return Error{errorType: NoType, originalError: wrappedError}
+	return layer
 }
 
-func WrapfWithCode(code ErrorCode, err error, msg string, args ...interface{}) Error {
-	wrappedError := errors.Wrapf(err, msg, args...)
-	if customErr, ok := err.(Error); ok {
-		return Error{
-			code:          customErr.code,
-			errorType:     customErr.errorType,
-			originalError: wrappedError,
-			context:       customErr.context,
+// Layer describes an Error of a chain.
+type Layer struct {
+	Type ErrorType
+	Code ErrorCode
+	// Message is the message added by the layer.
+	Message string
+}
+
+// Chain returns the layers of err from the outermost Error to the innermost.
+// The type and code of a layer are the effective ones, see Wrapf.
+func Chain(err error) []Layer {
+	var found []Error
+	for ; err != nil; err = Unwrap(err) {
+		if customErr, ok := err.(Error); ok {
+			found = append(found, customErr)
 		}
 	}
 
-	return Error{code: code, errorType: NoType, originalError: wrappedError}
+	layers := make([]Layer, len(found))
+	for i, customErr := range found {
+		message := customErr.Error()
+		if i+1 < len(found) {
+			message = strings.TrimSuffix(message, ": "+found[i+1].Error())
+		}
+		layers[i] = Layer{Type: customErr.errorType, Code: customErr.code, Message: message}
+	}
+	return layers
+}
+
+func (e Error) Chain() []Layer {
+	return Chain(e)
 }
 
+// GetType returns the type of the first Error in the chain of err.
 func GetType(err error) ErrorType {
-	if customErr, ok := err.(Error); ok {
+	var customErr Error
+	if As(err, &customErr) {
 		return customErr.errorType
 	}
 
 	return NoType
 }
 
-func Is(err error, target Error) bool {
-	if customErr, ok := err.(Error); ok {
-		if customErr.originalError == target.originalError {
-			return true
-		}
+// GetCode returns the code of the first Error in the chain of err.
+func GetCode(err error) ErrorCode {
+	var customErr Error
+	if As(err, &customErr) {
+		return customErr.code
 	}
 
-	return false
+	return 0
+}
+
+// Is reports whether any error in the chain of err matches the target.
+func Is(err error, target error) bool {
+	return stdErrors.Is(err, target)
+}
+
+// As finds the first error in the chain of err assignable to the target.
+func As(err error, target interface{}) bool {
+	return stdErrors.As(err, target)
+}
+
+// Unwrap returns the error wrapped by err or nil.
+func Unwrap(err error) error {
+	return stdErrors.Unwrap(err)
 }
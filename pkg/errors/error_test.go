@@ -0,0 +1,48 @@
+package errors
+
+import "testing"
+
+func TestIsMultiOriginals(t *testing.T) {
+	var first, second Multi
+	first.Append(Validation.New("first"))
+	second.Append(Validation.New("second"))
+
+	var e1, e2 Error
+	if !As(first, &e1) || !As(second, &e2) {
+		t.Fatal("Multi is not an Error")
+	}
+
+	if e1.Is(e2) {
+		t.Error("errors of different batches match")
+	}
+
+	target := Internal.New("target")
+	if !Is(Wrap(target, "wrapped"), target) {
+		t.Error("wrapped error does not match its target")
+	}
+}
+
+func TestIsDecodedMultiOriginals(t *testing.T) {
+	var multi Multi
+	multi.Append(Validation.New("first"), NotFound.New("second"))
+
+	data, err := Wrap(multi, "batch").MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var e1, e2 Error
+	if err := e1.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := e2.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if e1.Is(e2) {
+		t.Error("separately decoded errors match")
+	}
+	if !e1.Is(e1) {
+		t.Error("decoded error does not match itself")
+	}
+}
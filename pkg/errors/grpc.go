@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"strconv"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// GRPCErrorDomain is the domain of the ErrorInfo detail carrying the type and
+// code of an Error in a gRPC status.
+const GRPCErrorDomain = "neon-service-framework"
+
+const grpcMetadataCode = "code"
+
+var grpcCodes = struct {
+	sync.RWMutex
+	codes map[ErrorType]codes.Code
+}{
+	codes: map[ErrorType]codes.Code{
+		Validation:   codes.InvalidArgument,
+		NotFound:     codes.NotFound,
+		AccessDenied: codes.PermissionDenied,
+		Unauthorized: codes.Unauthenticated,
+		Logical:      codes.FailedPrecondition,
+		Temporarily:  codes.Unavailable,
+		Internal:     codes.Internal,
+		Critical:     codes.Internal,
+	},
+}
+
+// grpcTypes maps the codes of statuses without an ErrorInfo detail.
+var grpcTypes = map[codes.Code]ErrorType{
+	codes.InvalidArgument:    Validation,
+	codes.OutOfRange:         Validation,
+	codes.NotFound:           NotFound,
+	codes.PermissionDenied:   AccessDenied,
+	codes.Unauthenticated:    Unauthorized,
+	codes.FailedPrecondition: Logical,
+	codes.AlreadyExists:      Logical,
+	codes.Unavailable:        Temporarily,
+	codes.ResourceExhausted:  Temporarily,
+	codes.DeadlineExceeded:   Temporarily,
+	codes.Aborted:            Temporarily,
+	codes.Internal:           Internal,
+	codes.DataLoss:           Critical,
+}
+
+// RegisterGRPCCode sets the gRPC code of the error type, e.g. for the error
+// types defined by a service. It replaces the code of built-in types.
+func RegisterGRPCCode(errorType ErrorType, code codes.Code) {
+	grpcCodes.Lock()
+	defer grpcCodes.Unlock()
+	grpcCodes.codes[errorType] = code
+}
+
+// GRPCCode returns the gRPC code of the error type, Unknown for types without
+// a registered code.
+func GRPCCode(errorType ErrorType) codes.Code {
+	grpcCodes.RLock()
+	defer grpcCodes.RUnlock()
+
+	if code, ok := grpcCodes.codes[errorType]; ok {
+		return code
+	}
+	return codes.Unknown
+}
+
+// GRPCCodeOf returns the gRPC code of the Error in the chain of err. The
+// override of the registered definition of its code is preferred.
+func GRPCCodeOf(err error) codes.Code {
+	var customErr Error
+	if !As(err, &customErr) {
+		return codes.Unknown
+	}
+	if def, ok := definitionOf(customErr); ok && def.GRPCCode != codes.OK {
+		return def.GRPCCode
+	}
+	return GRPCCode(customErr.errorType)
+}
+
+// ToGRPCStatus converts err to a status with the code of its type. The type
+// and code are sent in an ErrorInfo detail and the context in a Struct detail.
+// Errors which are statuses already are returned as is.
+func ToGRPCStatus(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	var customErr Error
+	if !As(err, &customErr) {
+		return status.New(codes.Unknown, err.Error())
+	}
+
+	st := status.New(GRPCCodeOf(customErr), err.Error())
+
+	info := &errdetails.ErrorInfo{
+		Reason:   customErr.errorType.String(),
+		Domain:   GRPCErrorDomain,
+		Metadata: map[string]string{},
+	}
+	if customErr.code != 0 {
+		info.Metadata[grpcMetadataCode] = strconv.FormatUint(uint64(customErr.code), 10)
+	}
+	details := []protoiface.MessageV1{info}
+
+	if customErr.context.Len() > 0 {
+		if fields, err := customErr.context.normalize(); err == nil {
+			if context, err := structpb.NewStruct(fields); err == nil {
+				details = append(details, context)
+			}
+		}
+	}
+
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails
+	}
+	return st
+}
+
+// FromGRPCStatus converts the status back to an Error. The type is taken from
+// the ErrorInfo detail of ToGRPCStatus or derived from the status code.
+func FromGRPCStatus(st *status.Status) Error {
+	customErr := New(st.Message())
+	customErr.errorType = grpcTypes[st.Code()]
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			if detail.GetDomain() != GRPCErrorDomain {
+				continue
+			}
+			if errorType, ok := ParseErrorType(detail.GetReason()); ok {
+				customErr.errorType = errorType
+			}
+			if code, err := strconv.ParseUint(detail.GetMetadata()[grpcMetadataCode], 10, 64); err == nil {
+				customErr.code = ErrorCode(code)
+			}
+		case *structpb.Struct:
+			customErr.context = detail.AsMap()
+		}
+	}
+
+	return customErr
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"net/http"
+	"sync"
+)
+
+var httpStatuses = struct {
+	sync.RWMutex
+	statuses map[ErrorType]int
+}{
+	statuses: map[ErrorType]int{
+		Validation:   http.StatusBadRequest,
+		Logical:      http.StatusBadRequest,
+		AccessDenied: http.StatusForbidden,
+		Unauthorized: http.StatusUnauthorized,
+		NotFound:     http.StatusNotFound,
+		Temporarily:  http.StatusServiceUnavailable,
+	},
+}
+
+// RegisterHTTPStatus sets the HTTP status of the error type, e.g. for the
+// error types defined by a service. It replaces the status of built-in types.
+func RegisterHTTPStatus(errorType ErrorType, status int) {
+	httpStatuses.Lock()
+	defer httpStatuses.Unlock()
+	httpStatuses.statuses[errorType] = status
+}
+
+// HTTPStatus returns the HTTP status of the error type, 500 for types without
+// a registered status.
+func HTTPStatus(errorType ErrorType) int {
+	httpStatuses.RLock()
+	defer httpStatuses.RUnlock()
+
+	if status, ok := httpStatuses.statuses[errorType]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// HTTPStatusOf returns the HTTP status of the Error in the chain of err. The
+// override of the registered definition of its code is preferred.
+func HTTPStatusOf(err error) int {
+	var customErr Error
+	if !As(err, &customErr) {
+		return http.StatusInternalServerError
+	}
+	if def, ok := definitionOf(customErr); ok && def.HTTPStatus != 0 {
+		return def.HTTPStatus
+	}
+	return HTTPStatus(customErr.errorType)
+}
+
+// HTTPStatusType returns the error type of the HTTP status, e.g. for the
+// error responses of other services. Statuses without a registered type give
+// Temporarily for rate limits and unavailable or timed out gateways, Internal
+// for other server errors and NoType otherwise.
+func HTTPStatusType(status int) ErrorType {
+	httpStatuses.RLock()
+	defer httpStatuses.RUnlock()
+
+	for t := NoType; t <= Critical; t++ {
+		if s, ok := httpStatuses.statuses[t]; ok && s == status {
+			return t
+		}
+	}
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return Temporarily
+	}
+	if status >= http.StatusInternalServerError {
+		return Internal
+	}
+	return NoType
+}
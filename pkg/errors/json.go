@@ -0,0 +1,96 @@
+package errors
+
+import "encoding/json"
+
+// errorJSON is the JSON form of an Error. It has the fields of the error
+// model of the api responses, so that they can be decoded as an Error.
+// Members are set for the errors of a Multi in the chain.
+type errorJSON struct {
+	Message string                 `json:"message"`
+	Name    string                 `json:"name"`
+	Code    ErrorCode              `json:"code,omitempty"`
+	Context map[string]interface{} `json:"context,omitempty"`
+	Errors  []errorJSON            `json:"errors,omitempty"`
+}
+
+// remoteError is the original error of an unmarshaled Error. It keeps the
+// message and the members of the Multi, so that the Error is rendered as the
+// one it was marshaled from.
+type remoteError struct {
+	message string
+	multi   error
+}
+
+func (e *remoteError) Error() string {
+	return e.message
+}
+
+func (e *remoteError) Unwrap() error {
+	return e.multi
+}
+
+// MarshalJSON encodes the message, type, code and context of the error, e.g.
+// to pass it to another service. UnmarshalJSON restores it.
+func (e Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newErrorJSON(e))
+}
+
+// UnmarshalJSON restores an Error encoded by MarshalJSON. The stack of the
+// error is not restored.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var v errorJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*e = v.toError()
+	return nil
+}
+
+func newErrorJSON(err error) errorJSON {
+	v := errorJSON{Name: NoType.String()}
+	if err == nil {
+		return v
+	}
+
+	// the zero Error has no message
+	if e, ok := err.(Error); !ok || e.originalError != nil {
+		v.Message = err.Error()
+	}
+
+	var customErr Error
+	if As(err, &customErr) {
+		v.Name = customErr.errorType.String()
+		v.Code = customErr.code
+		v.Context = customErr.context
+	}
+
+	var multi Multi
+	if As(err, &multi) {
+		for _, member := range multi.Errors() {
+			v.Errors = append(v.Errors, newErrorJSON(member))
+		}
+	}
+
+	return v
+}
+
+func (v errorJSON) toError() Error {
+	errorType, _ := ParseErrorType(v.Name)
+	// a pointer, so that Error.Is compares the identity of the original
+	original := &remoteError{message: v.Message}
+
+	if len(v.Errors) > 0 {
+		var multi Multi
+		for _, member := range v.Errors {
+			multi.Append(member.toError())
+		}
+		original.multi = multi
+	}
+
+	return Error{
+		code:          v.Code,
+		errorType:     errorType,
+		originalError: original,
+		context:       v.Context,
+	}
+}
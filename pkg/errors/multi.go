@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"strconv"
+	"strings"
+)
+
+// typePriority orders the types from the most to the least severe. The type
+// of a Multi is the most severe type of its members, types missing here rank
+// like NoType.
+var typePriority = []ErrorType{Critical, Internal, NoType, Temporarily, Unauthorized, AccessDenied, NotFound, Logical, Validation}
+
+func priority(errorType ErrorType) int {
+	for i, t := range typePriority {
+		if t == errorType {
+			return i
+		}
+	}
+	return priority(NoType)
+}
+
+// Multi collects the errors of a batch operation, e.g.
+//
+//	var errs errors.Multi
+//	for _, tx := range txs {
+//		errs.Append(validate(tx))
+//	}
+//	return errs.ErrorOrNil()
+//
+// errors.Is and errors.As look into every member. errors.As to an Error gives
+// the Error of the batch with the most severe type of the members.
+type Multi struct {
+	errs []error
+}
+
+// Append adds the errors, nil errors are skipped.
+func (m *Multi) Append(errs ...error) {
+	for _, err := range errs {
+		if err != nil {
+			m.errs = append(m.errs, err)
+		}
+	}
+}
+
+func (m Multi) Len() int {
+	return len(m.errs)
+}
+
+func (m Multi) Errors() []error {
+	return m.errs
+}
+
+// ErrorOrNil returns nil when no errors were appended.
+func (m Multi) ErrorOrNil() error {
+	if len(m.errs) == 0 {
+		return nil
+	}
+	return m
+}
+
+func (m Multi) Error() string {
+	if len(m.errs) == 1 {
+		return m.errs[0].Error()
+	}
+
+	messages := make([]string, len(m.errs))
+	for i, err := range m.errs {
+		messages[i] = err.Error()
+	}
+	return strconv.Itoa(len(m.errs)) + " errors occurred: " + strings.Join(messages, "; ")
+}
+
+func (m Multi) Unwrap() []error {
+	return m.errs
+}
+
+// As sets an Error target to the Error of the batch. It has the most severe
+// type of the members and their code when all members share it.
+func (m Multi) As(target interface{}) bool {
+	customErr, ok := target.(*Error)
+	if !ok || len(m.errs) == 0 {
+		return false
+	}
+	*customErr = Error{errorType: m.Type(), code: m.code(), originalError: m}
+	return true
+}
+
+// Type returns the most severe type of the members.
+func (m Multi) Type() ErrorType {
+	result := Validation
+	for i, err := range m.errs {
+		if t := GetType(err); i == 0 || priority(t) < priority(result) {
+			result = t
+		}
+	}
+	return result
+}
+
+func (m Multi) code() ErrorCode {
+	var code ErrorCode
+	for i, err := range m.errs {
+		c := GetCode(err)
+		if i > 0 && c != code {
+			return 0
+		}
+		code = c
+	}
+	return code
+}
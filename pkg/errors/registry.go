@@ -0,0 +1,179 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Definition declares an error code of the catalog.
+type Definition struct {
+	Code ErrorCode
+	Type ErrorType
+	// Message is the default message, a template for the args of New.
+	Message string
+	// Doc describes the error for the catalog.
+	Doc string
+	// HTTPStatus overrides the status of the type.
+	// Optional. Default value 0, the status of the type.
+	HTTPStatus int
+	// GRPCCode overrides the code of the type.
+	// Optional. Default value codes.OK, the code of the type.
+	GRPCCode codes.Code
+}
+
+// New creates an error of the definition, formatting the message with args.
+func (d Definition) New(args ...interface{}) Error {
+	if len(args) == 0 {
+		return d.Type.NewWithCode(d.Code, d.Message)
+	}
+	return d.Type.NewfWithCode(d.Code, d.Message, args...)
+}
+
+// Wrap wraps err with the code, type and message of the definition.
+func (d Definition) Wrap(err error, args ...interface{}) Error {
+	return d.Type.WrapfWithCode(d.Code, err, d.Message, args...)
+}
+
+// Registry holds the definitions by code.
+type Registry struct {
+	mu          sync.RWMutex
+	definitions map[ErrorCode]Definition
+}
+
+// DefaultRegistry holds the definitions of the framework and the services.
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{definitions: make(map[ErrorCode]Definition)}
+}
+
+// Register adds the definition. It panics on a zero or an already registered
+// code, so collisions are found at init.
+func (r *Registry) Register(def Definition) Definition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if def.Code == 0 {
+		panic(fmt.Sprintf("errors: definition %q has no code", def.Message))
+	}
+	if registered, ok := r.definitions[def.Code]; ok {
+		panic(fmt.Sprintf("errors: code %d of %q is already registered for %q", def.Code, def.Message, registered.Message))
+	}
+	r.definitions[def.Code] = def
+
+	return def
+}
+
+// Lookup returns the definition of the code.
+func (r *Registry) Lookup(code ErrorCode) (Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	def, ok := r.definitions[code]
+	return def, ok
+}
+
+// Definitions returns the definitions ordered by code.
+func (r *Registry) Definitions() []Definition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	definitions := make([]Definition, 0, len(r.definitions))
+	for _, def := range r.definitions {
+		definitions = append(definitions, def)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Code < definitions[j].Code
+	})
+	return definitions
+}
+
+// Register adds the definition to the DefaultRegistry.
+func Register(def Definition) Definition {
+	return DefaultRegistry.Register(def)
+}
+
+// Lookup returns the definition of the code from the DefaultRegistry.
+func Lookup(code ErrorCode) (Definition, bool) {
+	return DefaultRegistry.Lookup(code)
+}
+
+// definitionOf returns the definition of the code of the error. It applies
+// only while the error keeps the type of the definition.
+func definitionOf(err Error) (Definition, bool) {
+	if err.code == 0 {
+		return Definition{}, false
+	}
+	def, ok := DefaultRegistry.Lookup(err.code)
+	if !ok || def.Type != err.errorType {
+		return Definition{}, false
+	}
+	return def, true
+}
+
+// CatalogEntry describes a definition with the statuses it is mapped to.
+type CatalogEntry struct {
+	Code       ErrorCode `json:"code"`
+	Type       string    `json:"type"`
+	Message    string    `json:"message"`
+	Doc        string    `json:"doc,omitempty"`
+	HTTPStatus int       `json:"http_status"`
+	GRPCCode   string    `json:"grpc_code"`
+}
+
+// Catalog returns the entries of the definitions ordered by code.
+func (r *Registry) Catalog() []CatalogEntry {
+	definitions := r.Definitions()
+	entries := make([]CatalogEntry, len(definitions))
+	for i, def := range definitions {
+		entry := CatalogEntry{
+			Code:       def.Code,
+			Type:       def.Type.String(),
+			Message:    def.Message,
+			Doc:        def.Doc,
+			HTTPStatus: def.HTTPStatus,
+			GRPCCode:   def.GRPCCode.String(),
+		}
+		if entry.HTTPStatus == 0 {
+			entry.HTTPStatus = HTTPStatus(def.Type)
+		}
+		if def.GRPCCode == codes.OK {
+			entry.GRPCCode = GRPCCode(def.Type).String()
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+// WriteJSON writes the catalog as a JSON array.
+func (r *Registry) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.Catalog())
+}
+
+// WriteMarkdown writes the catalog as a Markdown table.
+func (r *Registry) WriteMarkdown(w io.Writer) error {
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+
+	if _, err := fmt.Fprintln(w, "| Code | Type | HTTP | gRPC | Message | Description |"); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "|---|---|---|---|---|---|"); err != nil {
+		return err
+	}
+	for _, entry := range r.Catalog() {
+		_, err := fmt.Fprintf(w, "| %d | %s | %d | %s | %s | %s |\n",
+			entry.Code, entry.Type, entry.HTTPStatus, entry.GRPCCode, escape.Replace(entry.Message), escape.Replace(entry.Doc))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// StackPolicy defines which errors capture the stack trace where they are
+// created. Capturing is the main cost of creating an error.
+type StackPolicy int32
+
+const (
+	// StackAlways captures the stack of every error.
+	StackAlways = StackPolicy(iota)
+	// StackSevere captures the stack of Internal and Critical errors only.
+	StackSevere
+	// StackNever captures no stacks.
+	StackNever
+)
+
+var stackPolicy int32
+
+// SetStackPolicy sets the policy of the errors created afterwards.
+func SetStackPolicy(policy StackPolicy) {
+	atomic.StoreInt32(&stackPolicy, int32(policy))
+}
+
+// ParseStackPolicy parses "always", "severe" or "never".
+func ParseStackPolicy(policy string) (StackPolicy, error) {
+	switch strings.ToLower(policy) {
+	case "always", "":
+		return StackAlways, nil
+	case "severe":
+		return StackSevere, nil
+	case "never":
+		return StackNever, nil
+	}
+	return StackAlways, fmt.Errorf("unknown stack policy %q", policy)
+}
+
+func captureStack(errorType ErrorType) bool {
+	switch StackPolicy(atomic.LoadInt32(&stackPolicy)) {
+	case StackNever:
+		return false
+	case StackSevere:
+		return errorType == Internal || errorType == Critical
+	}
+	return true
+}
+
+type stackTracer interface {
+	StackTrace() errors.StackTrace
+}
+
+func newOriginal(errorType ErrorType, msg string) error {
+	if captureStack(errorType) {
+		return errors.New(msg)
+	}
+	return stdErrors.New(msg)
+}
+
+// newOriginalf supports the %w verb.
+func newOriginalf(errorType ErrorType, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	if captureStack(errorType) {
+		return errors.WithStack(err)
+	}
+	return err
+}
+
+// wrapOriginal captures the stack only once per chain, the innermost stack
+// points to where the error happened.
+func wrapOriginal(errorType ErrorType, err error, msg string, args ...interface{}) error {
+	if captureStack(errorType) && innermostStack(err) == nil {
+		return errors.Wrapf(err, msg, args...)
+	}
+	return errors.WithMessagef(err, msg, args...)
+}
+
+// StackTrace returns the innermost stack captured in the chain of the error,
+// nil when the stack policy skipped it.
+func (e Error) StackTrace() errors.StackTrace {
+	return innermostStack(e)
+}
+
+// Stack returns the innermost stack of the chain of err as "file:line function"
+// lines.
+func Stack(err error) []string {
+	stack := innermostStack(err)
+	lines := make([]string, len(stack))
+	for i, frame := range stack {
+		lines[i] = fmt.Sprintf("%s:%d %n", frame, frame, frame)
+	}
+	return lines
+}
+
+var packagePrefix = reflect.TypeOf(Error{}).PkgPath() + "."
+
+// innermostStack returns the stack without the frames of this package.
+func innermostStack(err error) errors.StackTrace {
+	var stack errors.StackTrace
+	for ; err != nil; err = Unwrap(err) {
+		if _, ok := err.(Error); ok {
+			continue
+		}
+		if tracer, ok := err.(stackTracer); ok {
+			stack = tracer.StackTrace()
+		}
+	}
+
+	for len(stack) > 0 {
+		fn := runtime.FuncForPC(uintptr(stack[0]) - 1)
+		if fn == nil || !strings.HasPrefix(fn.Name(), packagePrefix) {
+			break
+		}
+		stack = stack[1:]
+	}
+	return stack
+}
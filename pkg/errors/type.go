@@ -1,11 +1,5 @@
 package errors
 
-import (
-	"fmt"
-
-	"github.com/pkg/errors"
-)
-
 type ErrorType uint
 
 func (t ErrorType) String() string {
@@ -31,42 +25,48 @@ func (t ErrorType) String() string {
 	}
 }
 
+// ParseErrorType returns the type of the name given by String.
+func ParseErrorType(name string) (ErrorType, bool) {
+	for t := NoType; t <= Critical; t++ {
+		if t.String() == name {
+			return t, true
+		}
+	}
+	return NoType, false
+}
+
 func (t ErrorType) New(msg string) Error {
-	return Error{errorType: t, originalError: errors.New(msg)}
+	return Error{errorType: t, originalError: newOriginal(t, msg)}
 }
 
 func (t ErrorType) Newf(msg string, args ...interface{}) Error {
-	err := fmt.Errorf(msg, args...)
-
-	return Error{errorType: t, originalError: err}
+	return Error{errorType: t, originalError: newOriginalf(t, msg, args...)}
 }
 
 func (t ErrorType) NewWithCode(code ErrorCode, msg string) Error {
-	return Error{code: code, errorType: t, originalError: errors.New(msg)}
+	return Error{code: code, errorType: t, originalError: newOriginal(t, msg)}
 }
 
 func (t ErrorType) NewfWithCode(code ErrorCode, msg string, args ...interface{}) Error {
-	err := fmt.Errorf(msg, args...)
-
-	return Error{code: code, errorType: t, originalError: err}
+	return Error{code: code, errorType: t, originalError: newOriginalf(t, msg, args...)}
 }
 
 func (t ErrorType) Wrap(err error, msg string) Error {
 	return t.Wrapf(err, msg)
 }
 
+// Wrapf adds a layer of the type, keeping the code and context of the Error
+// in the chain of err.
 func (t ErrorType) Wrapf(err error, msg string, args ...interface{}) Error {
-	newErr := errors.Wrapf(err, msg, args...)
-
-	return Error{errorType: t, originalError: newErr}
+	return wrap(t, 0, err, msg, args...)
 }
 
 func (t ErrorType) WrapWithCode(code ErrorCode, err error, msg string) Error {
 	return t.WrapfWithCode(code, err, msg)
 }
 
+// WrapfWithCode adds a layer of the type and code, keeping the context of the
+// Error in the chain of err.
 func (t ErrorType) WrapfWithCode(code ErrorCode, err error, msg string, args ...interface{}) Error {
-	newErr := errors.Wrapf(err, msg, args...)
-
-	return Error{code: code, errorType: t, originalError: newErr}
+	return wrap(t, code, err, msg, args...)
 }
@@ -0,0 +1,110 @@
+package eth
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"golang.org/x/crypto/sha3"
+)
+
+const AddressLength = 20
+
+// Address is an Ethereum account address. It is encoded as an EIP-55 checksum
+// hex string.
+type Address [AddressLength]byte
+
+// ParseAddress parses a 0x prefixed hex address. Mixed case addresses must
+// have a valid EIP-55 checksum, lower and upper case ones are accepted as is.
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	if !IsHexAddress(s) {
+		return a, errors.Validation.Newf("invalid address %q", s)
+	}
+
+	b, _ := hex.DecodeString(s[2:])
+	copy(a[:], b)
+
+	body := s[2:]
+	if body != strings.ToLower(body) && body != strings.ToUpper(body) && a.Hex() != "0x"+body {
+		return Address{}, errors.Validation.Newf("invalid address checksum %q", s)
+	}
+
+	return a, nil
+}
+
+// IsHexAddress reports whether the string is a 0x prefixed hex address. The
+// checksum is not verified.
+func IsHexAddress(s string) bool {
+	return Has0xPrefix(s) && len(s) == 2+2*AddressLength && isHexCharacters(s[2:])
+}
+
+// IsChecksumAddress reports whether the string is an address in its EIP-55
+// checksum form.
+func IsChecksumAddress(s string) bool {
+	if !IsHexAddress(s) {
+		return false
+	}
+	var a Address
+	b, _ := hex.DecodeString(s[2:])
+	copy(a[:], b)
+	return a.Hex() == "0x"+s[2:]
+}
+
+func BytesToAddress(b []byte) Address {
+	var a Address
+	if len(b) > AddressLength {
+		b = b[len(b)-AddressLength:]
+	}
+	copy(a[AddressLength-len(b):], b)
+	return a
+}
+
+func (a Address) Bytes() []byte {
+	return a[:]
+}
+
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// Hex returns the EIP-55 checksum encoding of the address.
+func (a Address) Hex() string {
+	lower := hex.EncodeToString(a[:])
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(lower))
+	digest := hash.Sum(nil)
+
+	result := []byte(lower)
+	for i := range result {
+		nibble := digest[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if result[i] >= 'a' && nibble&0x0f >= 8 {
+			result[i] -= 'a' - 'A'
+		}
+	}
+
+	return "0x" + string(result)
+}
+
+func (a Address) String() string {
+	return a.Hex()
+}
+
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.Hex()), nil
+}
+
+func (a *Address) UnmarshalText(text []byte) (err error) {
+	*a, err = ParseAddress(string(text))
+	return err
+}
+
+// UnmarshalParam implements echo.BindUnmarshaler.
+func (a *Address) UnmarshalParam(param string) (err error) {
+	*a, err = ParseAddress(param)
+	return err
+}
@@ -0,0 +1,44 @@
+package eth
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+// Has0xPrefix reports whether the string starts with "0x" or "0X".
+func Has0xPrefix(s string) bool {
+	return len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X')
+}
+
+// IsHex reports whether the string is a 0x prefixed hex string of even length.
+func IsHex(s string) bool {
+	if !Has0xPrefix(s) || len(s)%2 != 0 {
+		return false
+	}
+	return isHexCharacters(s[2:])
+}
+
+// DecodeHex decodes a 0x prefixed hex string.
+func DecodeHex(s string) ([]byte, error) {
+	if !Has0xPrefix(s) {
+		return nil, errors.Validation.Newf("hex string %q has no 0x prefix", s)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, errors.Validation.Wrapf(err, "invalid hex string %q", s)
+	}
+	return b, nil
+}
+
+// EncodeHex encodes the bytes as a 0x prefixed hex string.
+func EncodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func isHexCharacters(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return !('0' <= r && r <= '9' || 'a' <= r && r <= 'f' || 'A' <= r && r <= 'F')
+	}) < 0
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
 	"github.com/rs/zerolog"
 )
 
@@ -124,15 +125,43 @@ func (zle *ZeroLogEvent) Msgf(format string, v ...interface{}) {
 	zle.event.Msgf(format, v...)
 }
 
-func (zle *ZeroLogEvent) Err(err error) Event {
-	message := "nil"
+// errorFields is the "error" field of the events. The type, code, context and
+// stack are set for errors.Error, the members of errors.Multi are listed.
+type errorFields struct {
+	Message string
+	Type    string                 `json:"Type,omitempty"`
+	Code    errors.ErrorCode       `json:"Code,omitempty"`
+	Context map[string]interface{} `json:"Context,omitempty"`
+	Stack   []string               `json:"Stack,omitempty"`
+	Errors  []errorFields          `json:"Errors,omitempty"`
+}
+
+func newErrorFields(err error) errorFields {
+	fields := errorFields{Message: "nil"}
 	if err != nil {
-		message = err.Error()
+		fields.Message = err.Error()
 	}
 
-	return &ZeroLogEvent{event: zle.event.Interface("error", struct {
-		Message string
-	}{Message: message})}
+	var perr errors.Error
+	if errors.As(err, &perr) {
+		fields.Type = perr.GetType().String()
+		fields.Code = perr.GetCode()
+		fields.Context = perr.GetContext()
+		fields.Stack = errors.Stack(err)
+	}
+
+	var multi errors.Multi
+	if errors.As(err, &multi) {
+		for _, member := range multi.Errors() {
+			fields.Errors = append(fields.Errors, newErrorFields(member))
+		}
+	}
+
+	return fields
+}
+
+func (zle *ZeroLogEvent) Err(err error) Event {
+	return &ZeroLogEvent{event: zle.event.Interface("error", newErrorFields(err))}
 }
 
 func (zle *ZeroLogEvent) Str(key string, val string) Event {
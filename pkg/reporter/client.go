@@ -0,0 +1,226 @@
+package reporter
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+	"golang.org/x/time/rate"
+)
+
+// sendTimeout bounds the delivery of a report.
+const sendTimeout = 10 * time.Second
+
+type (
+	// Config defines the config of a Reporter.
+	Config struct {
+		// Environment of the service, e.g. "production".
+		// Optional. Default value "".
+		Environment string
+
+		// Release is the version of the service.
+		// Optional. Default value "".
+		Release string
+
+		// ServerName is the host of the service.
+		// Optional. Default value os.Hostname().
+		ServerName string
+
+		// Tags are added to all reports.
+		// Optional. Default value nil.
+		Tags map[string]string
+
+		// DedupWindow is the period in which the same error is reported once.
+		// Optional. Default value 1 minute.
+		DedupWindow time.Duration
+
+		// RateLimit is the number of reports sent per second.
+		// Optional. Default value 1.
+		RateLimit float64
+
+		// Burst is the number of reports sent at once above the rate limit.
+		// Optional. Default value 10.
+		Burst int
+
+		// QueueSize is the number of reports waiting to be sent, reports are
+		// dropped when the queue is full.
+		// Optional. Default value 100.
+		QueueSize int
+
+		// Logger logs the reports which failed to be sent.
+		// Optional. Default value logger.Get().
+		Logger logger.Logger
+	}
+)
+
+var (
+	// DefaultConfig is the default Reporter config.
+	DefaultConfig = Config{
+		DedupWindow: time.Minute,
+		RateLimit:   1,
+		Burst:       10,
+		QueueSize:   100,
+	}
+)
+
+// Reporter is an ErrorReporter sending the reports through a Transport in the
+// background. Repeated errors are reported once per DedupWindow and the
+// reports are rate limited, so that a failing dependency does not flood the
+// error tracking service.
+type Reporter struct {
+	transport Transport
+	config    Config
+	limiter   *rate.Limiter
+	queue     chan *Report
+	pending   sync.WaitGroup
+
+	mu      sync.Mutex
+	seen    map[string]time.Time
+	stopped bool
+}
+
+// New starts a Reporter. It sends the queued reports and stops when the
+// context is done, the events reported after that are dropped.
+func New(ctx context.Context, transport Transport, config Config) *Reporter {
+	// Defaults
+	if config.ServerName == "" {
+		config.ServerName, _ = os.Hostname()
+	}
+	if config.DedupWindow <= 0 {
+		config.DedupWindow = DefaultConfig.DedupWindow
+	}
+	if config.RateLimit <= 0 {
+		config.RateLimit = DefaultConfig.RateLimit
+	}
+	if config.Burst <= 0 {
+		config.Burst = DefaultConfig.Burst
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultConfig.QueueSize
+	}
+	if config.Logger == nil {
+		config.Logger = logger.Get()
+	}
+
+	r := &Reporter{
+		transport: transport,
+		config:    config,
+		limiter:   rate.NewLimiter(rate.Limit(config.RateLimit), config.Burst),
+		queue:     make(chan *Report, config.QueueSize),
+		seen:      make(map[string]time.Time),
+	}
+	go r.run(ctx)
+
+	return r
+}
+
+func (r *Reporter) Report(_ context.Context, event Event) {
+	if event.Err == nil {
+		return
+	}
+
+	report := newReport(event)
+	report.ID = newID()
+	report.Environment = r.config.Environment
+	report.Release = r.config.Release
+	report.ServerName = r.config.ServerName
+	for k, v := range r.config.Tags {
+		if _, ok := report.Tags[k]; !ok {
+			report.Tags[k] = v
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped || r.duplicate(report.Fingerprint, report.Timestamp) || !r.limiter.Allow() {
+		return
+	}
+
+	r.pending.Add(1)
+	select {
+	case r.queue <- report:
+		// dropped reports are not marked, so the next occurrence is reported
+		r.markSeen(report.Fingerprint, report.Timestamp)
+	default:
+		r.pending.Done()
+		r.config.Logger.Warn().Str("error", report.Message).Msg("error report is dropped, the queue is full")
+	}
+}
+
+func (r *Reporter) Flush(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		r.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (r *Reporter) run(ctx context.Context) {
+	for {
+		select {
+		case report := <-r.queue:
+			r.send(report)
+		case <-ctx.Done():
+			// the reports queued before the shutdown are still sent
+			r.mu.Lock()
+			r.stopped = true
+			r.mu.Unlock()
+			for {
+				select {
+				case report := <-r.queue:
+					r.send(report)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *Reporter) send(report *Report) {
+	defer r.pending.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+	defer cancel()
+
+	if err := r.transport.Send(ctx, report); err != nil {
+		r.config.Logger.Warn().Err(err).Str("event_id", report.ID).Msg("failed to send error report")
+	}
+}
+
+// duplicate reports whether the fingerprint was seen in the dedup window. The
+// lock must be held.
+func (r *Reporter) duplicate(fingerprint string, now time.Time) bool {
+	seen, ok := r.seen[fingerprint]
+	return ok && now.Sub(seen) < r.config.DedupWindow
+}
+
+// markSeen starts the dedup window of the fingerprint. The lock must be held.
+func (r *Reporter) markSeen(fingerprint string, now time.Time) {
+	if len(r.seen) >= 1024 {
+		for k, seen := range r.seen {
+			if now.Sub(seen) >= r.config.DedupWindow {
+				delete(r.seen, k)
+			}
+		}
+	}
+	r.seen[fingerprint] = now
+}
+
+func newID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
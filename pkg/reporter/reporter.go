@@ -0,0 +1,200 @@
+package reporter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+const (
+	LevelError = "error"
+	LevelFatal = "fatal"
+)
+
+// Frame is a frame of the stack of a reported error.
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// Event is an unexpected error or a recovered panic.
+type Event struct {
+	Err error
+	// Panic is set for errors recovered from panics.
+	Panic bool
+	// Frames is the stack from the innermost frame. Optional, the stack of
+	// the error is used when empty.
+	Frames []Frame
+	// Tags describe where the error happened, e.g. the transport and route.
+	Tags map[string]string
+}
+
+// ErrorReporter sends unexpected errors to an error tracking service.
+type ErrorReporter interface {
+	// Report queues the event without blocking.
+	Report(ctx context.Context, event Event)
+	// Flush waits until the queued events are sent, it reports whether they
+	// were sent before the timeout.
+	Flush(timeout time.Duration) bool
+}
+
+// ShouldReport reports whether the error is unexpected: Internal or Critical,
+// or an error without a type, e.g. a plain error, which is a server error
+// unless the definition of its code sets another HTTP status. The
+// cancellation of a request is not reported.
+func ShouldReport(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	switch errors.GetType(err) {
+	case errors.Internal, errors.Critical:
+		return true
+	case errors.NoType:
+		return errors.HTTPStatusOf(err) >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// ReportError reports the unexpected errors. The reporter may be nil.
+func ReportError(ctx context.Context, r ErrorReporter, err error, tags map[string]string) {
+	if r == nil || !ShouldReport(err) {
+		return
+	}
+	r.Report(ctx, Event{Err: err, Tags: tags})
+}
+
+// ReportPanic reports the value recovered from a panic. It must be called by
+// the deferred function which recovered it, the stack starts at the panic.
+func ReportPanic(ctx context.Context, r ErrorReporter, recovered interface{}, tags map[string]string) {
+	if r == nil {
+		return
+	}
+
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+
+	frames := CallerFrames(1)
+	for i, frame := range frames {
+		if frame.Function == "runtime.gopanic" {
+			frames = frames[i+1:]
+			break
+		}
+	}
+
+	r.Report(ctx, Event{Err: err, Panic: true, Frames: frames, Tags: tags})
+}
+
+// CallerFrames returns the stack of the caller, skipping skip frames above it.
+func CallerFrames(skip int) []Frame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(skip+2, pcs)
+
+	var frames []Frame
+	callers := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := callers.Next()
+		frames = append(frames, Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// FramesOf returns the stack captured in the chain of the error.
+func FramesOf(err error) []Frame {
+	var customErr errors.Error
+	if !errors.As(err, &customErr) {
+		return nil
+	}
+
+	stack := customErr.StackTrace()
+	frames := make([]Frame, 0, len(stack))
+	for _, frame := range stack {
+		fn := runtime.FuncForPC(uintptr(frame) - 1)
+		if fn == nil {
+			continue
+		}
+		file, line := fn.FileLine(uintptr(frame) - 1)
+		frames = append(frames, Frame{Function: fn.Name(), File: file, Line: line})
+	}
+	return frames
+}
+
+// Report is an event prepared for a Transport.
+type Report struct {
+	ID          string
+	Timestamp   time.Time
+	Level       string
+	Message     string
+	Type        string
+	Code        errors.ErrorCode
+	Context     map[string]interface{}
+	Frames      []Frame
+	Tags        map[string]string
+	Environment string
+	Release     string
+	ServerName  string
+	Fingerprint string
+}
+
+// Transport delivers the reports to an error tracking service.
+type Transport interface {
+	Send(ctx context.Context, report *Report) error
+}
+
+func newReport(event Event) *Report {
+	report := &Report{
+		Timestamp: time.Now().UTC(),
+		Level:     LevelError,
+		Message:   event.Err.Error(),
+		Type:      errors.GetType(event.Err).String(),
+		Code:      errors.GetCode(event.Err),
+		Frames:    event.Frames,
+		Tags:      make(map[string]string, len(event.Tags)),
+	}
+
+	var customErr errors.Error
+	if errors.As(event.Err, &customErr) {
+		report.Context = customErr.GetContext()
+	}
+	if len(report.Frames) == 0 {
+		report.Frames = FramesOf(event.Err)
+	}
+	if event.Panic {
+		report.Type = "panic"
+	}
+	if event.Panic || report.Type == errors.Critical.String() {
+		report.Level = LevelFatal
+	}
+	for k, v := range event.Tags {
+		report.Tags[k] = v
+	}
+
+	// the message may contain ids or values, which would make every
+	// occurrence unique, so the errors are told apart by the location, or by
+	// the tags for errors without a stack
+	location := ""
+	if len(report.Frames) > 0 {
+		location = fmt.Sprintf("%s:%d", report.Frames[0].Function, report.Frames[0].Line)
+	} else {
+		tags := make([]string, 0, len(report.Tags))
+		for k, v := range report.Tags {
+			tags = append(tags, k+"="+v)
+		}
+		sort.Strings(tags)
+		location = strings.Join(tags, ",")
+	}
+	report.Fingerprint = strings.Join([]string{report.Type, fmt.Sprint(report.Code), location}, "|")
+
+	return report
+}
@@ -0,0 +1,149 @@
+package reporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const sentryClient = "neon-service-framework/1.0"
+
+// SentryTransport sends the reports to the store endpoint of a Sentry
+// compatible service.
+type SentryTransport struct {
+	endpoint string
+	auth     string
+	client   *http.Client
+}
+
+// NewSentryTransport parses a DSN of the form
+// "https://public_key@host/project_id".
+func NewSentryTransport(dsn string) (*SentryTransport, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sentry dsn: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" || u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("invalid sentry dsn %q", dsn)
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	idx := strings.LastIndex(path, "/")
+	projectID := path[idx+1:]
+	if projectID == "" {
+		return nil, fmt.Errorf("invalid sentry dsn %q: project id is missing", dsn)
+	}
+
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", sentryClient, u.User.Username())
+	if secret, ok := u.User.Password(); ok {
+		auth += ", sentry_secret=" + secret
+	}
+
+	return &SentryTransport{
+		endpoint: fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, path[:idx], projectID),
+		auth:     auth,
+		client:   &http.Client{},
+	}, nil
+}
+
+type (
+	sentryEvent struct {
+		EventID     string                 `json:"event_id"`
+		Timestamp   string                 `json:"timestamp"`
+		Level       string                 `json:"level"`
+		Platform    string                 `json:"platform"`
+		ServerName  string                 `json:"server_name,omitempty"`
+		Environment string                 `json:"environment,omitempty"`
+		Release     string                 `json:"release,omitempty"`
+		Tags        map[string]string      `json:"tags,omitempty"`
+		Extra       map[string]interface{} `json:"extra,omitempty"`
+		Fingerprint []string               `json:"fingerprint,omitempty"`
+		Exception   sentryExceptions       `json:"exception"`
+	}
+
+	sentryExceptions struct {
+		Values []sentryException `json:"values"`
+	}
+
+	sentryException struct {
+		Type       string            `json:"type"`
+		Value      string            `json:"value"`
+		Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+	}
+
+	sentryStacktrace struct {
+		Frames []sentryFrame `json:"frames"`
+	}
+
+	sentryFrame struct {
+		Function string `json:"function"`
+		Filename string `json:"filename"`
+		AbsPath  string `json:"abs_path"`
+		Lineno   int    `json:"lineno"`
+	}
+)
+
+func (t *SentryTransport) Send(ctx context.Context, report *Report) error {
+	event := sentryEvent{
+		EventID:     report.ID,
+		Timestamp:   report.Timestamp.Format(time.RFC3339),
+		Level:       report.Level,
+		Platform:    "go",
+		ServerName:  report.ServerName,
+		Environment: report.Environment,
+		Release:     report.Release,
+		Tags:        report.Tags,
+		Extra:       report.Context,
+		Fingerprint: []string{report.Fingerprint},
+	}
+
+	exception := sentryException{Type: report.Type, Value: report.Message}
+	if report.Code != 0 {
+		exception.Type = fmt.Sprintf("%s %d", report.Type, report.Code)
+	}
+	if len(report.Frames) > 0 {
+		// sentry expects the innermost frame last
+		frames := make([]sentryFrame, len(report.Frames))
+		for i, frame := range report.Frames {
+			frames[len(frames)-1-i] = sentryFrame{
+				Function: frame.Function,
+				Filename: frame.File[strings.LastIndex(frame.File, "/")+1:],
+				AbsPath:  frame.File,
+				Lineno:   frame.Line,
+			}
+		}
+		exception.Stacktrace = &sentryStacktrace{Frames: frames}
+	}
+	event.Exception.Values = []sentryException{exception}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", t.auth)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sentry responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff computes the delay before the next attempt from the number of the
+// failed attempt, starting at 1, and the previous delay.
+type Backoff interface {
+	Next(attempt int, previous time.Duration) time.Duration
+}
+
+type constantBackoff struct {
+	delay time.Duration
+}
+
+// Constant waits the same delay before every attempt.
+func Constant(delay time.Duration) Backoff {
+	return constantBackoff{delay: delay}
+}
+
+func (b constantBackoff) Next(int, time.Duration) time.Duration {
+	return b.delay
+}
+
+type exponentialBackoff struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+}
+
+// Exponential multiplies the initial delay by the multiplier for every
+// attempt, up to max. The delays are spread by full jitter, a random delay
+// between zero and the computed one.
+func Exponential(initial time.Duration, maxDelay time.Duration, multiplier float64) Backoff {
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	return exponentialBackoff{initial: initial, max: maxDelay, multiplier: multiplier}
+}
+
+func (b exponentialBackoff) Next(attempt int, _ time.Duration) time.Duration {
+	delay := float64(b.initial) * math.Pow(b.multiplier, float64(attempt-1))
+	if delay > float64(b.max) || math.IsInf(delay, 0) {
+		delay = float64(b.max)
+	}
+	return randomDuration(0, time.Duration(delay))
+}
+
+type decorrelatedJitterBackoff struct {
+	base time.Duration
+	max  time.Duration
+}
+
+// DecorrelatedJitter picks a random delay between base and three times the
+// previous delay, up to max. It spreads the retries of many clients better
+// than Exponential.
+func DecorrelatedJitter(base time.Duration, maxDelay time.Duration) Backoff {
+	return decorrelatedJitterBackoff{base: base, max: maxDelay}
+}
+
+func (b decorrelatedJitterBackoff) Next(_ int, previous time.Duration) time.Duration {
+	if previous < b.base {
+		previous = b.base
+	}
+	upper := previous * 3
+	if upper > b.max || upper < previous {
+		upper = b.max
+	}
+	return randomDuration(b.base, upper)
+}
+
+func randomDuration(lower time.Duration, upper time.Duration) time.Duration {
+	if upper <= lower {
+		return lower
+	}
+	return lower + time.Duration(rand.Int63n(int64(upper-lower)+1))
+}
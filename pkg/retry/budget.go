@@ -0,0 +1,43 @@
+package retry
+
+import "sync"
+
+// Budget limits the retries to a downstream service, so that retries do not
+// overload a service which is already failing. Failed attempts take a token
+// and successful calls give back a part of one. Retries are allowed while more
+// than half of the tokens are left.
+type Budget struct {
+	mu        sync.Mutex
+	maxTokens float64
+	ratio     float64
+	tokens    float64
+}
+
+// NewBudget creates a full budget. With maxTokens 10 and ratio 0.1 about one
+// retry per ten successful calls is allowed once the service keeps failing.
+func NewBudget(maxTokens float64, ratio float64) *Budget {
+	return &Budget{maxTokens: maxTokens, ratio: ratio, tokens: maxTokens}
+}
+
+// Allow reports whether a retry is allowed.
+func (b *Budget) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens > b.maxTokens/2
+}
+
+func (b *Budget) onFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens -= 1; b.tokens < 0 {
+		b.tokens = 0
+	}
+}
+
+func (b *Budget) onSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens += b.ratio; b.tokens > b.maxTokens {
+		b.tokens = b.maxTokens
+	}
+}
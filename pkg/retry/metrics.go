@@ -0,0 +1,30 @@
+package retry
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	outcomeSuccess         = "success"
+	outcomeRetry           = "retry"
+	outcomeFailure         = "failure"
+	outcomeCanceled        = "canceled"
+	outcomeBudgetExhausted = "budget_exhausted"
+)
+
+var (
+	metricsOnce sync.Once
+
+	attemptsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "retry_attempts_total",
+		Help: "Attempts of retried operations by operation and outcome.",
+	}, []string{"operation", "outcome"})
+)
+
+func initMetrics() {
+	metricsOnce.Do(func() {
+		prometheus.MustRegister(attemptsTotal)
+	})
+}
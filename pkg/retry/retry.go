@@ -0,0 +1,135 @@
+package retry
+
+import (
+	"context"
+	"time"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+)
+
+type (
+	// Config defines the config of a Retrier.
+	Config struct {
+		// Name of the operation in metrics and logs.
+		// Optional. Default value "default".
+		Name string
+
+		// MaxAttempts is the number of attempts including the first one.
+		// Optional. Default value 3.
+		MaxAttempts int
+
+		// Backoff computes the delays between the attempts.
+		// Optional. Default value Exponential(100ms, 5s, 2).
+		Backoff Backoff
+
+		// Retryable reports whether the error of an attempt is retried.
+		// Optional. Default value IsRetryable.
+		Retryable func(err error) bool
+
+		// Budget limits the retries, it may be shared by the retriers of
+		// the calls to a downstream service.
+		// Optional. Default value nil, no limit.
+		Budget *Budget
+
+		// Logger logs the retries.
+		// Optional. Default value logger.Get().
+		Logger logger.Logger
+	}
+)
+
+var (
+	// DefaultConfig is the default Retrier config.
+	DefaultConfig = Config{
+		Name:        "default",
+		MaxAttempts: 3,
+		Backoff:     Exponential(100*time.Millisecond, 5*time.Second, 2),
+		Retryable:   IsRetryable,
+	}
+)
+
+// Retrier runs operations until they succeed, fail with an error which is not
+// retryable or run out of attempts.
+type Retrier struct {
+	config Config
+}
+
+func New(config Config) *Retrier {
+	// Defaults
+	if config.Name == "" {
+		config.Name = DefaultConfig.Name
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultConfig.MaxAttempts
+	}
+	if config.Backoff == nil {
+		config.Backoff = DefaultConfig.Backoff
+	}
+	if config.Retryable == nil {
+		config.Retryable = DefaultConfig.Retryable
+	}
+	if config.Logger == nil {
+		config.Logger = logger.Get()
+	}
+
+	initMetrics()
+
+	return &Retrier{config: config}
+}
+
+// Do runs the operation. It returns the error of the last attempt, or the
+// error of the context when it is done before the first attempt.
+func (r *Retrier) Do(ctx context.Context, operation func(ctx context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		attemptsTotal.WithLabelValues(r.config.Name, outcomeCanceled).Inc()
+		return err
+	}
+
+	var delay time.Duration
+
+	for attempt := 1; ; attempt++ {
+		err := operation(ctx)
+		if err == nil {
+			attemptsTotal.WithLabelValues(r.config.Name, outcomeSuccess).Inc()
+			if r.config.Budget != nil {
+				r.config.Budget.onSuccess()
+			}
+			return nil
+		}
+
+		if !r.config.Retryable(err) || attempt >= r.config.MaxAttempts {
+			attemptsTotal.WithLabelValues(r.config.Name, outcomeFailure).Inc()
+			return err
+		}
+
+		if r.config.Budget != nil {
+			r.config.Budget.onFailure()
+			if !r.config.Budget.Allow() {
+				attemptsTotal.WithLabelValues(r.config.Name, outcomeBudgetExhausted).Inc()
+				r.config.Logger.Warn().Err(err).Str("operation", r.config.Name).Int("attempt", attempt).Msg("retry budget is exhausted")
+				return err
+			}
+		}
+
+		delay = r.config.Backoff.Next(attempt, delay)
+		attemptsTotal.WithLabelValues(r.config.Name, outcomeRetry).Inc()
+		r.config.Logger.Warn().Err(err).Str("operation", r.config.Name).Int("attempt", attempt).Str("delay", delay.String()).Msg("retrying operation")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			attemptsTotal.WithLabelValues(r.config.Name, outcomeCanceled).Inc()
+			return err
+		case <-timer.C:
+			if ctx.Err() != nil {
+				attemptsTotal.WithLabelValues(r.config.Name, outcomeCanceled).Inc()
+				return err
+			}
+		}
+	}
+}
+
+// Do runs the operation with a Retrier of the config.
+func Do(ctx context.Context, config Config, operation func(ctx context.Context) error) error {
+	return New(config).Do(ctx, operation)
+}
@@ -0,0 +1,44 @@
+package retry
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/http"
+
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"github.com/neonlabsorg/neon-service-framework/pkg/database"
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+)
+
+// solanaTransientCodes are the JSON-RPC error codes of Solana nodes which are
+// behind or have not processed the requested data yet.
+var solanaTransientCodes = map[int]bool{
+	-32004: true, // block not available for slot
+	-32005: true, // node is unhealthy
+	-32014: true, // block status not yet available
+	-32016: true, // minimum context slot has not been reached
+}
+
+// IsRetryable reports whether the error is Temporarily or a known transient
+// error of the Postgres, ClickHouse and Solana RPC clients. Cancellation of
+// the context is never retried.
+func IsRetryable(err error) bool {
+	if err == nil || stdErrors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.As(err, new(errors.Error)) {
+		return errors.GetType(err) == errors.Temporarily
+	}
+
+	var httpErr *jsonrpc.HTTPError
+	if stdErrors.As(err, &httpErr) {
+		return httpErr.Code == http.StatusTooManyRequests || httpErr.Code >= http.StatusInternalServerError
+	}
+	var rpcErr *jsonrpc.RPCError
+	if stdErrors.As(err, &rpcErr) {
+		return solanaTransientCodes[rpcErr.Code]
+	}
+
+	return errors.GetType(database.Translate(err)) == errors.Temporarily
+}
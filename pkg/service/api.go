@@ -2,12 +2,17 @@ package service
 
 import (
 	"context"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api/auth"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api/openapi"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api/stream"
 	"github.com/neonlabsorg/neon-service-framework/pkg/echo/binder"
 	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+	"github.com/neonlabsorg/neon-service-framework/pkg/reporter"
 	"github.com/neonlabsorg/neon-service-framework/pkg/service/configuration"
 )
 
@@ -16,7 +21,10 @@ type ApiServer struct {
 	ctx      context.Context
 	cfg      *configuration.ApiServerConfiguration
 	extender api.ApiContextExtender
+	docs     *openapi.Builder
 	logger   logger.Logger
+	reporter reporter.ErrorReporter
+	streams  stream.Routes
 }
 
 func NewApiServer(
@@ -30,6 +38,7 @@ func NewApiServer(
 		cfg:      cfg,
 		logger:   log,
 		extender: extender,
+		docs:     openapi.NewBuilder("", ""),
 	}
 
 	s.server = s.newEcho()
@@ -41,15 +50,26 @@ func (s *ApiServer) newEcho() *echo.Echo {
 	e := echo.New()
 
 	// Middleware
-	e.Use(middleware.Logger())
-	e.Use(middleware.BodyLimit(s.cfg.BodyLimit))
+	// set first so that the errors of the other middleware are problems too
+	if s.cfg.ProblemJSON {
+		e.Use(api.ProblemJSONWithConfig(api.ProblemConfig{TypeURI: s.cfg.ProblemTypeURI}))
+	}
+	// streaming connections are long-lived and must not be buffered or limited
+	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
+		Skipper: s.streams.IsStreamingRoute,
+	}))
+	e.Use(middleware.BodyLimitWithConfig(middleware.BodyLimitConfig{
+		Skipper: s.streams.IsStreamingRoute,
+		Limit:   s.cfg.BodyLimit,
+	}))
+	e.Use(binder.MultipartCleanup())
 	if s.cfg.UseCORS {
 		e.Use(middleware.CORS())
 	}
 
 	e.HTTPErrorHandler = api.HttpErrorHandler
 
-	e.Binder = new(binder.ModelBinder)
+	e.Binder = &binder.ModelBinder{StrictJSON: s.cfg.StrictJSON}
 
 	return e
 }
@@ -63,12 +83,115 @@ func (s *ApiServer) RegisterRoutes(handler func(server *echo.Echo) error) (err e
 	return nil
 }
 
+// AddStreamingRoutes marks the routes of WebSocket and SSE endpoints, which
+// are not logged and have no body limit.
+func (s *ApiServer) AddStreamingRoutes(paths ...string) {
+	s.streams.Add(paths...)
+}
+
 func (s *ApiServer) UseMiddleware(middlware echo.MiddlewareFunc) {
 	s.server.Use(middlware)
 }
 
+// UseAuth registers the authentication middleware configured by the
+// NS_API_AUTH_* env variables.
+func (s *ApiServer) UseAuth() (err error) {
+	cfg := s.cfg.Auth
+	if cfg == nil || !cfg.Enable {
+		return nil
+	}
+
+	var authenticators []auth.Authenticator
+
+	if cfg.UseJWT() {
+		jwtAuthenticator, err := auth.NewJWTAuthenticator(s.ctx, auth.JWTConfig{
+			Secret:              cfg.JWTSecret,
+			PublicKeyFile:       cfg.JWTPublicKeyFile,
+			JWKSFile:            cfg.JWKSFile,
+			JWKSURL:             cfg.JWKSURL,
+			JWKSRefreshInterval: cfg.JWKSRefreshInterval,
+			Algorithms:          cfg.JWTAlgorithms,
+			Audience:            cfg.JWTAudience,
+			Issuer:              cfg.JWTIssuer,
+		})
+		if err != nil {
+			return err
+		}
+		authenticators = append(authenticators, jwtAuthenticator)
+	}
+
+	if cfg.UseAPIKeys() {
+		keys, err := auth.ParseAPIKeys(cfg.APIKeys, false)
+		if err != nil {
+			return err
+		}
+		hashedKeys, err := auth.ParseAPIKeys(cfg.APIKeyHashes, true)
+		if err != nil {
+			return err
+		}
+		apiKeyAuthenticator, err := auth.NewAPIKeyAuthenticator(cfg.APIKeyHeader, append(keys, hashedKeys...))
+		if err != nil {
+			return err
+		}
+		authenticators = append(authenticators, apiKeyAuthenticator)
+	}
+
+	s.server.Use(auth.Middleware(auth.Config{
+		Skipper: func(c echo.Context) bool {
+			return skipAuthPath(cfg.SkipPaths, c.Path())
+		},
+		Authenticators: authenticators,
+		Optional:       cfg.Optional,
+	}))
+
+	return nil
+}
+
+// skipAuthPath matches the route against the skip paths. Paths ending with
+// "/" match the routes under them, so "/docs/" does not match "/docsadmin".
+func skipAuthPath(skipPaths []string, route string) bool {
+	for _, path := range skipPaths {
+		if path == "" {
+			continue
+		}
+		if route == path || strings.HasSuffix(path, "/") && strings.HasPrefix(route, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ApiServer) GetDocumentation() *openapi.Builder {
+	return s.docs
+}
+
+func (s *ApiServer) registerDocumentation() {
+	if s.cfg.UseOpenApi {
+		s.docs.Register(s.server, s.cfg.UseOpenApiUI)
+	}
+}
+
 func (s *ApiServer) SetCustomExtender(extender api.ApiContextExtender) {
 	s.extender = extender
+	if s.reporter != nil {
+		s.setExtenderErrorReporter()
+	}
+}
+
+// SetErrorReporter reports the unexpected errors handled by the server and the
+// panics recovered by the Recover middleware of the extender.
+func (s *ApiServer) SetErrorReporter(r reporter.ErrorReporter) {
+	s.reporter = r
+	s.server.HTTPErrorHandler = api.HttpErrorHandlerWithReporter(r)
+	s.setExtenderErrorReporter()
+}
+
+func (s *ApiServer) setExtenderErrorReporter() {
+	if extender, ok := s.extender.(interface {
+		SetErrorReporter(r reporter.ErrorReporter)
+	}); ok {
+		extender.SetErrorReporter(s.reporter)
+	}
 }
 
 func (s *ApiServer) registerExtender() {
@@ -78,6 +201,7 @@ func (s *ApiServer) registerExtender() {
 func (s *ApiServer) Run() (err error) {
 
 	s.registerExtender()
+	s.registerDocumentation()
 
 	go func() {
 		if err := s.server.Start(s.cfg.ListenAddr); err != nil {
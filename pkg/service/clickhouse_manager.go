@@ -7,6 +7,7 @@ import (
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/neonlabsorg/neon-service-framework/pkg/database"
 	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
 	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
 	"github.com/neonlabsorg/neon-service-framework/pkg/service/configuration"
@@ -113,3 +114,14 @@ func (m *ClickhouseManager) MustGetConnection(db string) (conn driver.Conn) {
 
 	return conn
 }
+
+// GetTranslatedConnection returns the connection wrapped to translate the
+// driver errors to errors.Error, see database.TranslateClickhouse.
+func (m *ClickhouseManager) GetTranslatedConnection(db string) (conn *database.ClickhouseConn, err error) {
+	c, err := m.GetConnection(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return database.NewClickhouseConn(c), nil
+}
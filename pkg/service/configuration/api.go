@@ -3,16 +3,28 @@ package configuration
 import "github.com/neonlabsorg/neon-service-framework/pkg/env"
 
 type ApiServerConfiguration struct {
-	ListenAddr string
-	UseCORS    bool
-	BodyLimit  string
+	ListenAddr     string
+	UseCORS        bool
+	BodyLimit      string
+	UseOpenApi     bool
+	UseOpenApiUI   bool
+	StrictJSON     bool
+	ProblemJSON    bool
+	ProblemTypeURI string
+	Auth           *ApiAuthConfiguration
 }
 
 func (c *ServiceConfiguration) loadApiServerConfiguration() (err error) {
 	c.ApiServer = &ApiServerConfiguration{
-		ListenAddr: env.Get("NS_API_LISTEN_ADDR", "0.0.0.0:8080"),
-		UseCORS:    env.GetBool("NS_API_USE_CORS", true),
-		BodyLimit:  env.Get("NS_API_BODY_LIMIT", "2M"),
+		ListenAddr:     env.Get("NS_API_LISTEN_ADDR", "0.0.0.0:8080"),
+		UseCORS:        env.GetBool("NS_API_USE_CORS", true),
+		BodyLimit:      env.Get("NS_API_BODY_LIMIT", "2M"),
+		UseOpenApi:     env.GetBool("NS_API_USE_OPENAPI", false),
+		UseOpenApiUI:   env.GetBool("NS_API_USE_OPENAPI_UI", false),
+		StrictJSON:     env.GetBool("NS_API_STRICT_JSON", false),
+		ProblemJSON:    env.GetBool("NS_API_PROBLEM_JSON", false),
+		ProblemTypeURI: env.Get("NS_API_PROBLEM_TYPE_URI"),
+		Auth:           c.loadApiAuthConfiguration(),
 	}
 
 	return nil
@@ -0,0 +1,55 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/env"
+)
+
+type ApiAuthConfiguration struct {
+	Enable   bool
+	Optional bool
+	// SkipPaths are the routes without authentication. A path ending with "/"
+	// skips all routes under it, other paths skip the route itself.
+	SkipPaths           []string
+	JWTSecret           string
+	JWTPublicKeyFile    string
+	JWKSFile            string
+	JWKSURL             string
+	JWKSRefreshInterval time.Duration
+	JWTAlgorithms       []string
+	JWTAudience         string
+	JWTIssuer           string
+	APIKeyHeader        string
+	// APIKeys and APIKeyHashes are "name:key[:scopes[:roles]]" entries
+	// separated by ';', see auth.ParseAPIKeys.
+	APIKeys      string
+	APIKeyHashes string
+}
+
+func (c *ServiceConfiguration) loadApiAuthConfiguration() *ApiAuthConfiguration {
+	return &ApiAuthConfiguration{
+		Enable:              env.GetBool("NS_API_AUTH_ENABLE", false),
+		Optional:            env.GetBool("NS_API_AUTH_OPTIONAL", false),
+		SkipPaths:           env.GetStringList("NS_API_AUTH_SKIP_PATHS", ";", []string{"/openapi.json", "/docs", "/docs/"}),
+		JWTSecret:           env.Get("NS_API_AUTH_JWT_SECRET"),
+		JWTPublicKeyFile:    env.Get("NS_API_AUTH_JWT_PUBLIC_KEY_FILE"),
+		JWKSFile:            env.Get("NS_API_AUTH_JWKS_FILE"),
+		JWKSURL:             env.Get("NS_API_AUTH_JWKS_URL"),
+		JWKSRefreshInterval: env.GetDuration("NS_API_AUTH_JWKS_REFRESH_INTERVAL", time.Minute*10),
+		JWTAlgorithms:       env.GetStringList("NS_API_AUTH_JWT_ALGORITHMS", ";"),
+		JWTAudience:         env.Get("NS_API_AUTH_JWT_AUDIENCE"),
+		JWTIssuer:           env.Get("NS_API_AUTH_JWT_ISSUER"),
+		APIKeyHeader:        env.Get("NS_API_AUTH_API_KEY_HEADER", "X-API-Key"),
+		APIKeys:             env.Get("NS_API_AUTH_API_KEYS"),
+		APIKeyHashes:        env.Get("NS_API_AUTH_API_KEY_HASHES"),
+	}
+}
+
+func (c *ApiAuthConfiguration) UseJWT() bool {
+	return c.JWTSecret != "" || c.JWTPublicKeyFile != "" || c.JWKSFile != "" || c.JWKSURL != ""
+}
+
+func (c *ApiAuthConfiguration) UseAPIKeys() bool {
+	return c.APIKeys != "" || c.APIKeyHashes != ""
+}
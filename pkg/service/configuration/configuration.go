@@ -12,6 +12,7 @@ type ServiceConfiguration struct {
 	MetricsServer *MetricsServerConfiguration
 	GRPCServer    *GRPCServerConfiguration
 	ApiServer     *ApiServerConfiguration
+	ErrorReporter *ErrorReporterConfiguration
 }
 
 // INIT CONFIGURATION
@@ -48,5 +49,9 @@ func NewServiceConfiguration(cfg *Config) (serviceConfiguration *ServiceConfigur
 		return nil, err
 	}
 
+	if err = serviceConfiguration.loadErrorReporterConfiguration(); err != nil {
+		return nil, err
+	}
+
 	return serviceConfiguration, nil
 }
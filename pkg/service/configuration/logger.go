@@ -10,6 +10,8 @@ type LoggerConfiguration struct {
 	Level    string
 	UseFile  bool
 	FilePath string
+	// ErrorStack is the stack policy of errors: always, severe or never
+	ErrorStack string
 }
 
 // LOAD LOGGER CONFIGURATION
@@ -28,9 +30,10 @@ func (c *ServiceConfiguration) loadLoggerConfiguration() error {
 	}
 
 	cfg := &LoggerConfiguration{
-		Level:    level,
-		FilePath: path,
-		UseFile:  useFile,
+		Level:      level,
+		FilePath:   path,
+		UseFile:    useFile,
+		ErrorStack: env.Get("NS_LOG_ERROR_STACK", "always"),
 	}
 
 	c.Logger = cfg
@@ -0,0 +1,26 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/env"
+)
+
+type ErrorReporterConfiguration struct {
+	// SentryDSN enables the reports of unexpected errors and panics.
+	SentryDSN   string
+	DedupWindow time.Duration
+	RateLimit   float64
+	Burst       int
+}
+
+func (c *ServiceConfiguration) loadErrorReporterConfiguration() (err error) {
+	c.ErrorReporter = &ErrorReporterConfiguration{
+		SentryDSN:   env.Get("NS_ERROR_REPORTER_SENTRY_DSN"),
+		DedupWindow: env.GetDuration("NS_ERROR_REPORTER_DEDUP_WINDOW", time.Minute),
+		RateLimit:   env.GetFloat64("NS_ERROR_REPORTER_RATE_LIMIT", 1),
+		Burst:       env.GetInt("NS_ERROR_REPORTER_BURST", 10),
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+// errorCatalogCommand prints the registered error codes for the teams
+// consuming the API.
+func errorCatalogCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "errors-catalog",
+		Usage: "print the catalog of error codes",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "json or markdown",
+				Value: "json",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			switch format := c.String("format"); format {
+			case "json":
+				return errors.DefaultRegistry.WriteJSON(os.Stdout)
+			case "markdown", "md":
+				return errors.DefaultRegistry.WriteMarkdown(os.Stdout)
+			default:
+				return fmt.Errorf("unknown catalog format %q", format)
+			}
+		},
+	}
+}
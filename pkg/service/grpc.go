@@ -1,10 +1,13 @@
 package service
 
 import (
+	"context"
 	"net"
 
 	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
+	"github.com/neonlabsorg/neon-service-framework/pkg/reporter"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 )
 
 type GRPCServiceCollectionItem struct {
@@ -25,6 +28,7 @@ func (c *GRPCServiceCollection) Add(svc *GRPCServiceCollectionItem) {
 type GRPCServer struct {
 	listenAddr string
 	services   GRPCServiceCollection
+	reporter   reporter.ErrorReporter
 }
 
 func NewGRPCServer(listenAddr string) *GRPCServer {
@@ -40,6 +44,12 @@ func (s *GRPCServer) RegisterService(svc *grpc.ServiceDesc, srv interface{}) {
 	})
 }
 
+// SetErrorReporter sets the reporter of the unexpected errors and panics of
+// the handlers. It must be set before the server runs.
+func (s *GRPCServer) SetErrorReporter(r reporter.ErrorReporter) {
+	s.reporter = r
+}
+
 func (s *GRPCServer) registerServices(srv *grpc.Server) {
 	for _, item := range s.services {
 		srv.RegisterService(item.ServiceDesc, item.ServerInterface)
@@ -52,8 +62,93 @@ func (s *GRPCServer) Run() (err error) {
 		return errors.Critical.Wrap(err, "failed on network listener on")
 	}
 
-	srv := grpc.NewServer()
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerErrorInterceptor, s.unaryReportInterceptor),
+		grpc.ChainStreamInterceptor(StreamServerErrorInterceptor, s.streamReportInterceptor),
+	)
 	s.registerServices(srv)
 
 	return srv.Serve(lis)
 }
+
+// UnaryServerErrorInterceptor converts the errors returned by handlers to
+// statuses with the code, type and context of the errors.Error.
+func UnaryServerErrorInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, errors.ToGRPCStatus(err).Err()
+	}
+	return resp, nil
+}
+
+// StreamServerErrorInterceptor is UnaryServerErrorInterceptor for streams.
+func StreamServerErrorInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return errors.ToGRPCStatus(err).Err()
+	}
+	return nil
+}
+
+// unaryReportInterceptor reports the unexpected errors of the handlers and
+// turns their panics into Critical errors.
+func (s *GRPCServer) unaryReportInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	tags := grpcTags(info.FullMethod)
+	defer func() {
+		if r := recover(); r != nil {
+			reporter.ReportPanic(ctx, s.reporter, r, tags)
+			err = errors.Critical.Newf("panic: %v", r)
+		}
+	}()
+
+	resp, err = handler(ctx, req)
+	if shouldReportGRPC(err) {
+		reporter.ReportError(ctx, s.reporter, err, tags)
+	}
+	return resp, err
+}
+
+// streamReportInterceptor is unaryReportInterceptor for streams.
+func (s *GRPCServer) streamReportInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	tags := grpcTags(info.FullMethod)
+	defer func() {
+		if r := recover(); r != nil {
+			reporter.ReportPanic(ss.Context(), s.reporter, r, tags)
+			err = errors.Critical.Newf("panic: %v", r)
+		}
+	}()
+
+	err = handler(srv, ss)
+	if shouldReportGRPC(err) {
+		reporter.ReportError(ss.Context(), s.reporter, err, tags)
+	}
+	return err
+}
+
+// shouldReportGRPC tells the statuses returned by the handlers apart by their
+// code, e.g. Unknown and Internal are reported but NotFound is not.
+func shouldReportGRPC(err error) bool {
+	if err == nil || errors.As(err, new(errors.Error)) {
+		return reporter.ShouldReport(err)
+	}
+	if st, ok := status.FromError(err); ok {
+		return reporter.ShouldReport(errors.FromGRPCStatus(st))
+	}
+	return reporter.ShouldReport(err)
+}
+
+func grpcTags(method string) map[string]string {
+	return map[string]string{
+		"transport": "grpc",
+		"method":    method,
+	}
+}
+
+// UnaryClientErrorInterceptor converts the statuses returned by calls back to
+// errors.Error, so that clients can match them by code and type.
+func UnaryClientErrorInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if st, ok := status.FromError(err); ok && err != nil {
+		return errors.FromGRPCStatus(st)
+	}
+	return err
+}
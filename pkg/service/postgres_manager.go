@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/neonlabsorg/neon-service-framework/pkg/database"
 	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
 	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
 	"github.com/neonlabsorg/neon-service-framework/pkg/service/configuration"
@@ -83,3 +84,14 @@ func (m *PostgresManager) MustGetConnectionPool(db string) (pool *pgxpool.Pool)
 
 	return pool
 }
+
+// GetTranslatedConnectionPool returns the pool wrapped to translate the driver
+// errors to errors.Error, see database.TranslatePostgres.
+func (m *PostgresManager) GetTranslatedConnectionPool(db string) (pool *database.PostgresPool, err error) {
+	p, err := m.GetConnectionPool(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return database.NewPostgresPool(p), nil
+}
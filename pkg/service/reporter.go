@@ -0,0 +1,71 @@
+package service
+
+import (
+	"time"
+
+	"github.com/neonlabsorg/neon-service-framework/pkg/reporter"
+	"github.com/neonlabsorg/neon-service-framework/pkg/service/configuration"
+)
+
+// reporterFlushTimeout bounds the wait for the reports on shutdown and
+// before a panic of a handler crashes the service.
+const reporterFlushTimeout = 5 * time.Second
+
+func (s *Service) initErrorReporter(cfg *configuration.ErrorReporterConfiguration) {
+	if cfg.SentryDSN == "" {
+		return
+	}
+
+	transport, err := reporter.NewSentryTransport(cfg.SentryDSN)
+	if err != nil {
+		s.GetLogger().Error().Err(err).Msg("can't initialize error reporter")
+		panic(err)
+	}
+
+	s.errorReporter = reporter.New(s.ctx, transport, reporter.Config{
+		Environment: s.env,
+		Release:     s.version,
+		Tags:        map[string]string{"service": s.name},
+		DedupWindow: cfg.DedupWindow,
+		RateLimit:   cfg.RateLimit,
+		Burst:       cfg.Burst,
+		Logger:      s.GetLogger(),
+	})
+}
+
+// SetErrorReporter sets the reporter of the unexpected errors of the api and
+// grpc servers and of the panics of the servers and handlers. It replaces the
+// reporter configured by NS_ERROR_REPORTER_SENTRY_DSN and must be set before
+// the service runs.
+func (s *Service) SetErrorReporter(r reporter.ErrorReporter) {
+	s.errorReporter = r
+	if s.apiServer != nil {
+		s.apiServer.SetErrorReporter(r)
+	}
+	if s.grpcServer != nil {
+		s.grpcServer.SetErrorReporter(r)
+	}
+}
+
+func (s *Service) GetErrorReporter() reporter.ErrorReporter {
+	return s.errorReporter
+}
+
+// runHandler reports the panic of the handler before it crashes the service.
+func (s *Service) runHandler(h func(s *Service)) {
+	defer func() {
+		if r := recover(); r != nil {
+			reporter.ReportPanic(s.ctx, s.errorReporter, r, map[string]string{"transport": "handler"})
+			s.flushErrorReporter()
+			panic(r)
+		}
+	}()
+
+	h(s)
+}
+
+func (s *Service) flushErrorReporter() {
+	if s.errorReporter != nil && !s.errorReporter.Flush(reporterFlushTimeout) {
+		s.GetLogger().Warn().Msg("error reports have not been sent before the timeout")
+	}
+}
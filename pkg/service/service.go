@@ -10,11 +10,16 @@ import (
 	"syscall"
 
 	"github.com/gagliardetto/solana-go/rpc"
+	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
 	"github.com/neonlabsorg/neon-service-framework/pkg/api"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api/jsonrpc"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api/openapi"
+	"github.com/neonlabsorg/neon-service-framework/pkg/api/stream"
 	"github.com/neonlabsorg/neon-service-framework/pkg/env"
 	"github.com/neonlabsorg/neon-service-framework/pkg/errors"
 	"github.com/neonlabsorg/neon-service-framework/pkg/logger"
+	"github.com/neonlabsorg/neon-service-framework/pkg/reporter"
 	"github.com/neonlabsorg/neon-service-framework/pkg/service/configuration"
 	"github.com/urfave/cli/v2"
 	"google.golang.org/grpc"
@@ -35,6 +40,7 @@ type Service struct {
 	solanaRpcClient *rpc.Client
 	grpcServer      *GRPCServer
 	apiServer       *ApiServer
+	errorReporter   reporter.ErrorReporter
 	handlers        []func(service *Service)
 }
 
@@ -67,6 +73,7 @@ func CreateService(
 	s.initLoggerManager(configuration.Logger)
 	s.initSolana()
 	s.initDatabases(configuration.Storage)
+	s.initErrorReporter(configuration.ErrorReporter)
 
 	if configuration.UseGRPCServer {
 		s.initGRPCServer(configuration.GRPCServer)
@@ -100,12 +107,13 @@ func (s *Service) run(cliContext *cli.Context) (err error) {
 	for _, handler := range s.handlers {
 		go func(h func(s *Service), wGroup *sync.WaitGroup) {
 			defer wGroup.Done()
-			h(s)
+			s.runHandler(h)
 		}(handler, &wg)
 	}
 
 	<-s.ctx.Done()
 	wg.Wait()
+	s.flushErrorReporter()
 
 	s.loggerManager.GetLogger().Info().Msgf("Service %s has been stopped", s.name)
 
@@ -114,6 +122,9 @@ func (s *Service) run(cliContext *cli.Context) (err error) {
 
 func (s *Service) initGRPCServer(cfg *configuration.GRPCServerConfiguration) {
 	s.grpcServer = NewGRPCServer(cfg.ListenAddr)
+	if s.errorReporter != nil {
+		s.grpcServer.SetErrorReporter(s.errorReporter)
+	}
 }
 
 func (s *Service) initApiServer(cfg *configuration.ApiServerConfiguration) {
@@ -125,6 +136,18 @@ func (s *Service) initApiServer(cfg *configuration.ApiServerConfiguration) {
 		extender,
 		s.GetLogger(),
 	)
+	s.apiServer.GetDocumentation().SetInfo(openapi.Info{
+		Title:   s.name,
+		Version: s.version,
+	})
+	if s.errorReporter != nil {
+		s.apiServer.SetErrorReporter(s.errorReporter)
+	}
+
+	if err := s.apiServer.UseAuth(); err != nil {
+		s.GetLogger().Error().Err(err).Msg("error on init api authentication")
+		panic(err)
+	}
 }
 
 func (s *Service) initDatabases(cfg *configuration.StorageConfiguration) {
@@ -184,12 +207,19 @@ func (s *Service) initLoggerManager(cfg *configuration.LoggerConfiguration) {
 	logger.SetDefaultLogger(log)
 
 	s.loggerManager = NewLoggerManager(log)
+
+	stackPolicy, err := errors.ParseStackPolicy(cfg.ErrorStack)
+	if err != nil {
+		log.Error().Err(err).Msg("invalid error stack policy")
+	}
+	errors.SetStackPolicy(stackPolicy)
 }
 
 func (s *Service) initCliApp(isConsoleApp bool) {
 	s.cliApp = cli.NewApp()
 	s.cliApp.Name = s.name
 	s.cliApp.Version = s.version
+	s.cliApp.Commands = append(s.cliApp.Commands, errorCatalogCommand())
 
 	if !isConsoleApp {
 		s.cliApp.Action = s.run
@@ -266,6 +296,52 @@ func (s *Service) RegisterApiRoutes(handler func(server *echo.Echo) error) (err
 	return s.apiServer.RegisterRoutes(handler)
 }
 
+// RegisterJsonRpcServer mounts the JSON-RPC endpoint on the api server.
+func (s *Service) RegisterJsonRpcServer(path string, server *jsonrpc.Server) (err error) {
+	return s.RegisterApiRoutes(func(e *echo.Echo) error {
+		e.POST(path, server.Handle)
+		return nil
+	})
+}
+
+// NewStreamHub creates a hub of WebSocket and SSE connections which is closed
+// together with the service.
+func (s *Service) NewStreamHub(cfg stream.Config) *stream.Hub {
+	return stream.NewHub(s.ctx, cfg, s.GetLogger())
+}
+
+// RegisterStreamHub mounts the WebSocket endpoint of the hub on path and the
+// SSE endpoint on path + "/events".
+func (s *Service) RegisterStreamHub(path string, hub *stream.Hub, upgrader websocket.Upgrader) (err error) {
+	return s.RegisterApiRoutes(func(e *echo.Echo) error {
+		e.GET(path, hub.WebSocketHandler(upgrader))
+		e.GET(path+"/events", hub.SSEHandler())
+		s.apiServer.AddStreamingRoutes(path, path+"/events")
+		return nil
+	})
+}
+
+// GetApiValidator returns the validator of the api server to register
+// validations and message translations.
+func (s *Service) GetApiValidator() *api.Validator {
+	if s.apiServer == nil {
+		s.GetLogger().Error().Msg("the api server is not initialized")
+		return nil
+	}
+	if extender, ok := s.apiServer.extender.(*api.DefaultApiContextExtender); ok {
+		return extender.GetValidator()
+	}
+	return nil
+}
+
+func (s *Service) GetApiDocumentation() *openapi.Builder {
+	if s.apiServer == nil {
+		s.GetLogger().Error().Msg("the api server is not initialized")
+		return nil
+	}
+	return s.apiServer.GetDocumentation()
+}
+
 func (s *Service) SetCustomExtenderForApiServer(extender api.ApiContextExtender) {
 	if s.apiServer == nil {
 		s.GetLogger().Error().Msg("the api server is not initialized")